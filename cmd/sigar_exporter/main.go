@@ -0,0 +1,56 @@
+// sigar_exporter serves every gosigar metric in the Prometheus text format.
+package main
+
+import (
+	"flag"
+	"log"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/scalingdata/gosigar/exporter/prometheus"
+)
+
+var (
+	listenAddress = flag.String("web.listen-address", ":9101", "Address to listen on for HTTP requests")
+	metricsPath   = flag.String("web.telemetry-path", "/metrics", "Path under which to expose metrics")
+	processes     = flag.Bool("collector.processes", false, "Export per-process metrics")
+	processFds    = flag.Bool("collector.processes.fds", false, "Export open fds of processes against their limits")
+	fsTimeout     = flag.Duration("collector.filesystem.timeout", 5*time.Second, "How long to wait for statfs on each mount")
+	procRoot      = flag.String("path.procfs", "", "procfs mountpoint (Linux only)")
+	sysRoot       = flag.String("path.sysfs", "", "sysfs mountpoint (Linux only)")
+	etcRoot       = flag.String("path.etc", "", "etc directory (Linux only)")
+)
+
+// Build the handler serving metrics at path, and a landing page linking to it at /
+func newHandler(exporter *prometheus.Exporter, path string) http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle(path, exporter)
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(`<html><head><title>Sigar Exporter</title></head><body>` +
+			`<h1>Sigar Exporter</h1><p><a href="` + path + `">Metrics</a></p></body></html>`))
+	})
+	return mux
+}
+
+func main() {
+	flag.Parse()
+
+	exporter := prometheus.New(newSigar(*procRoot, *sysRoot, *etcRoot), prometheus.Options{
+		Processes:         *processes,
+		ProcessFds:        *processFds,
+		FileSystemTimeout: *fsTimeout,
+	})
+
+	listener, err := net.Listen("tcp", *listenAddress)
+	if err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("Listening on %s", listener.Addr())
+	log.Fatal(http.Serve(listener, newHandler(exporter, *metricsPath)))
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/scalingdata/gosigar"
+	"github.com/scalingdata/gosigar/exporter/prometheus"
+	"github.com/scalingdata/gosigar/fakes"
+)
+
+func TestServesMetrics(t *testing.T) {
+	fakeSigar := fakes.NewFakeSigar()
+	fakeSigar.LoadAverage = sigar.LoadAverage{One: 1.5, Five: 1, Fifteen: 0.5}
+
+	server := httptest.NewServer(newHandler(prometheus.New(fakeSigar, prometheus.Options{}), "/metrics"))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/metrics")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected status 200, got=%d", resp.StatusCode)
+	}
+	if contentType := resp.Header.Get("Content-Type"); contentType != prometheus.ContentType {
+		t.Errorf("Expected content type %q, got=%q", prometheus.ContentType, contentType)
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(body), "node_load1 1.5\n") {
+		t.Errorf("Expected node_load1 in body, got=%s", body)
+	}
+}
+
+func TestServesLandingPage(t *testing.T) {
+	server := httptest.NewServer(newHandler(prometheus.New(fakes.NewFakeSigar(), prometheus.Options{}), "/metrics"))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("Expected status 200, got=%d", resp.StatusCode)
+	}
+
+	resp, err = http.Get(server.URL + "/nope")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected status 404, got=%d", resp.StatusCode)
+	}
+}
@@ -0,0 +1,13 @@
+package main
+
+import (
+	sigar "github.com/scalingdata/gosigar"
+)
+
+func newSigar(procRoot, sysRoot, etcRoot string) sigar.Sigar {
+	return sigar.New(sigar.Options{
+		ProcRoot: procRoot,
+		SysRoot:  sysRoot,
+		EtcRoot:  etcRoot,
+	})
+}
@@ -0,0 +1,12 @@
+// +build !linux
+
+package main
+
+import (
+	sigar "github.com/scalingdata/gosigar"
+)
+
+// The roots only apply to Linux
+func newSigar(procRoot, sysRoot, etcRoot string) sigar.Sigar {
+	return &sigar.ConcreteSigar{}
+}
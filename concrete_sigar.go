@@ -1,12 +1,19 @@
 package sigar
 
 import (
+	"context"
 	"time"
 )
 
 type ConcreteSigar struct{}
 
 func (c *ConcreteSigar) CollectCpuStats(collectionInterval time.Duration) (<-chan Cpu, chan<- struct{}) {
+	return collectCpuStats(collectionInterval, (*Cpu).Get)
+}
+
+// Periodically sample CPU usage with getCpu, sending the first value as-is
+// and every following value as a delta from the previous sample.
+func collectCpuStats(collectionInterval time.Duration, getCpu func(*Cpu) error) (<-chan Cpu, chan<- struct{}) {
 	// samplesCh is buffered to 1 value to immediately return first CPU sample
 	samplesCh := make(chan Cpu, 1)
 
@@ -17,7 +24,7 @@ func (c *ConcreteSigar) CollectCpuStats(collectionInterval time.Duration) (<-cha
 
 		// Immediately provide non-delta value.
 		// samplesCh is buffered to 1 value, so it will not block.
-		cpuUsage.Get()
+		getCpu(&cpuUsage)
 		samplesCh <- cpuUsage
 
 		ticker := time.NewTicker(collectionInterval)
@@ -27,7 +34,7 @@ func (c *ConcreteSigar) CollectCpuStats(collectionInterval time.Duration) (<-cha
 			case <-ticker.C:
 				previousCpuUsage := cpuUsage
 
-				cpuUsage.Get()
+				getCpu(&cpuUsage)
 
 				select {
 				case samplesCh <- cpuUsage.Delta(previousCpuUsage):
@@ -44,12 +51,73 @@ func (c *ConcreteSigar) CollectCpuStats(collectionInterval time.Duration) (<-cha
 	return samplesCh, stopCh
 }
 
+func (c *ConcreteSigar) CollectCpuListPercent(collectionInterval time.Duration) (<-chan CpuListPercent, chan<- struct{}) {
+	return collectCpuListPercent(collectionInterval, (*CpuList).Get)
+}
+
+// Periodically sample per-core CPU usage with getCpuList, sending the
+// utilization since boot first and then the utilization over each interval.
+func collectCpuListPercent(collectionInterval time.Duration, getCpuList func(*CpuList) error) (<-chan CpuListPercent, chan<- struct{}) {
+	// samplesCh is buffered to 1 value to immediately return first sample
+	samplesCh := make(chan CpuListPercent, 1)
+
+	stopCh := make(chan struct{})
+
+	go func() {
+		var cpuList CpuList
+
+		getCpuList(&cpuList)
+		samplesCh <- cpuList.Percent()
+
+		ticker := time.NewTicker(collectionInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				previousCpuList := cpuList
+
+				getCpuList(&cpuList)
+
+				select {
+				case samplesCh <- cpuList.Delta(previousCpuList).Percent():
+				default:
+					// Include default to avoid channel blocking
+				}
+
+			case <-stopCh:
+				return
+			}
+		}
+	}()
+
+	return samplesCh, stopCh
+}
+
+func (c *ConcreteSigar) GetCpu() (Cpu, error) {
+	cpu := Cpu{}
+	err := cpu.Get()
+	return cpu, err
+}
+
+func (c *ConcreteSigar) GetCpuList() (CpuList, error) {
+	l := CpuList{}
+	err := l.Get()
+	return l, err
+}
+
 func (c *ConcreteSigar) GetLoadAverage() (LoadAverage, error) {
 	l := LoadAverage{}
 	err := l.Get()
 	return l, err
 }
 
+func (c *ConcreteSigar) GetUptime() (Uptime, error) {
+	u := Uptime{}
+	err := u.Get()
+	return u, err
+}
+
 func (c *ConcreteSigar) GetMem() (Mem, error) {
 	m := Mem{}
 	err := m.Get()
@@ -62,14 +130,249 @@ func (c *ConcreteSigar) GetSwap() (Swap, error) {
 	return s, err
 }
 
+func (c *ConcreteSigar) GetFileSystemList() (FileSystemList, error) {
+	f := FileSystemList{}
+	err := f.Get()
+	return f, err
+}
+
+// Mounts in the mount namespace of a process
+func (c *ConcreteSigar) GetProcFileSystemList(pid int) (FileSystemList, error) {
+	f := FileSystemList{}
+	err := f.GetPid(pid)
+	return f, err
+}
+
 func (c *ConcreteSigar) GetFileSystemUsage(path string) (FileSystemUsage, error) {
 	f := FileSystemUsage{}
 	err := f.Get(path)
 	return f, err
 }
 
+func (c *ConcreteSigar) GetDirUsage(path string, options DirUsageOptions) (DirUsage, error) {
+	d := DirUsage{}
+	err := d.GetContext(context.Background(), path, options)
+	return d, err
+}
+
+func (c *ConcreteSigar) GetDiskList() (DiskList, error) {
+	d := DiskList{}
+	err := d.Get()
+	return d, err
+}
+
+func (c *ConcreteSigar) GetNetProtoV4Stats() (NetProtoV4Stats, error) {
+	n := NetProtoV4Stats{}
+	err := n.Get()
+	return n, err
+}
+
+func (c *ConcreteSigar) GetNetProtoV6Stats() (NetProtoV6Stats, error) {
+	n := NetProtoV6Stats{}
+	err := n.Get()
+	return n, err
+}
+
+func (c *ConcreteSigar) GetNetIfaceList() (NetIfaceList, error) {
+	n := NetIfaceList{}
+	err := n.Get()
+	return n, err
+}
+
+func (c *ConcreteSigar) GetNetTcpConnList() (NetTcpConnList, error) {
+	n := NetTcpConnList{}
+	err := n.Get()
+	return n, err
+}
+
+func (c *ConcreteSigar) GetNetUdpConnList() (NetUdpConnList, error) {
+	n := NetUdpConnList{}
+	err := n.Get()
+	return n, err
+}
+
+func (c *ConcreteSigar) GetNetRawConnList() (NetRawConnList, error) {
+	n := NetRawConnList{}
+	err := n.Get()
+	return n, err
+}
+
+func (c *ConcreteSigar) GetNetTcpV6ConnList() (NetTcpV6ConnList, error) {
+	n := NetTcpV6ConnList{}
+	err := n.Get()
+	return n, err
+}
+
+func (c *ConcreteSigar) GetNetUdpV6ConnList() (NetUdpV6ConnList, error) {
+	n := NetUdpV6ConnList{}
+	err := n.Get()
+	return n, err
+}
+
+func (c *ConcreteSigar) GetNetRawV6ConnList() (NetRawV6ConnList, error) {
+	n := NetRawV6ConnList{}
+	err := n.Get()
+	return n, err
+}
+
+func (c *ConcreteSigar) GetNetConnList() (NetConnList, error) {
+	n := NetConnList{}
+	err := n.Get()
+	return n, err
+}
+
+func (c *ConcreteSigar) GetProcessList() (ProcessList, error) {
+	p := ProcessList{}
+	err := p.Get()
+	return p, err
+}
+
+func (c *ConcreteSigar) GetProcList() (ProcList, error) {
+	p := ProcList{}
+	err := p.Get()
+	return p, err
+}
+
+func (c *ConcreteSigar) GetProcState(pid int) (ProcState, error) {
+	p := ProcState{}
+	err := p.Get(pid)
+	return p, err
+}
+
+func (c *ConcreteSigar) GetProcStatus(pid int) (ProcStatus, error) {
+	p := ProcStatus{}
+	err := p.Get(pid)
+	return p, err
+}
+
+func (c *ConcreteSigar) GetProcIo(pid int) (ProcIo, error) {
+	p := ProcIo{}
+	err := p.Get(pid)
+	return p, err
+}
+
+func (c *ConcreteSigar) GetProcMem(pid int) (ProcMem, error) {
+	p := ProcMem{}
+	err := p.Get(pid)
+	return p, err
+}
+
+func (c *ConcreteSigar) GetProcTime(pid int) (ProcTime, error) {
+	p := ProcTime{}
+	err := p.Get(pid)
+	return p, err
+}
+
+func (c *ConcreteSigar) GetProcArgs(pid int) (ProcArgs, error) {
+	p := ProcArgs{}
+	err := p.Get(pid)
+	return p, err
+}
+
+func (c *ConcreteSigar) GetProcExe(pid int) (ProcExe, error) {
+	p := ProcExe{}
+	err := p.Get(pid)
+	return p, err
+}
+
+func (c *ConcreteSigar) GetProcFd(pid int) (ProcFd, error) {
+	p := ProcFd{}
+	err := p.Get(pid)
+	return p, err
+}
+
+func (c *ConcreteSigar) GetProcLimits(pid int) (ProcLimits, error) {
+	p := ProcLimits{}
+	err := p.Get(pid)
+	return p, err
+}
+
+func (c *ConcreteSigar) GetProcFdUsageList() (ProcFdUsageList, error) {
+	p := ProcFdUsageList{}
+	err := p.Get()
+	return p, err
+}
+
+func (c *ConcreteSigar) GetProcFdList(pid int) (ProcFdList, error) {
+	p := ProcFdList{}
+	err := p.Get(pid)
+	return p, err
+}
+
 func (c *ConcreteSigar) GetSystemInfo() (SystemInfo, error) {
 	s := SystemInfo{}
 	err := s.Get()
 	return s, err
 }
+
+func (c *ConcreteSigar) GetSystemDistribution() (SystemDistribution, error) {
+	d := SystemDistribution{}
+	err := d.Get()
+	return d, err
+}
+
+func (c *ConcreteSigar) GetKernelStat() (KernelStat, error) {
+	k := KernelStat{}
+	err := k.Get()
+	return k, err
+}
+
+func (c *ConcreteSigar) GetPressure() (Pressure, error) {
+	p := Pressure{}
+	err := p.Get()
+	return p, err
+}
+
+func (c *ConcreteSigar) GetMemInfo() (MemInfo, error) {
+	m := MemInfo{}
+	err := m.Get()
+	return m, err
+}
+
+func (c *ConcreteSigar) GetVmStat() (VmStat, error) {
+	v := VmStat{}
+	err := v.Get()
+	return v, err
+}
+
+func (c *ConcreteSigar) GetSwapList() (SwapList, error) {
+	l := SwapList{}
+	err := l.Get()
+	return l, err
+}
+
+func (c *ConcreteSigar) GetNumaNodeList() (NumaNodeList, error) {
+	n := NumaNodeList{}
+	err := n.Get()
+	return n, err
+}
+
+func (c *ConcreteSigar) GetCpuInfo() (CpuInfo, error) {
+	i := CpuInfo{}
+	err := i.Get()
+	return i, err
+}
+
+func (c *ConcreteSigar) GetBlockDeviceList() (BlockDeviceList, error) {
+	b := BlockDeviceList{}
+	err := b.Get()
+	return b, err
+}
+
+func (c *ConcreteSigar) GetMdArrayList() (MdArrayList, error) {
+	m := MdArrayList{}
+	err := m.Get()
+	return m, err
+}
+
+func (c *ConcreteSigar) GetDeletedOpenFiles() (DeletedOpenFiles, error) {
+	d := DeletedOpenFiles{}
+	err := d.Get()
+	return d, err
+}
+
+func (c *ConcreteSigar) GetSystemFd() (SystemFd, error) {
+	x := SystemFd{}
+	err := x.Get()
+	return x, err
+}
@@ -1,6 +1,8 @@
 package sigar_test
 
 import (
+	"context"
+	"os"
 	"runtime"
 	"time"
 
@@ -8,8 +10,12 @@ import (
 	. "github.com/scalingdata/gomega"
 
 	sigar "github.com/scalingdata/gosigar"
+	"github.com/scalingdata/gosigar/fakes"
 )
 
+var _ sigar.Sigar = &sigar.ConcreteSigar{}
+var _ sigar.Sigar = &fakes.FakeSigar{}
+
 var _ = Describe("ConcreteSigar", func() {
 	var concreteSigar *sigar.ConcreteSigar
 
@@ -87,7 +93,7 @@ var _ = Describe("ConcreteSigar", func() {
 		Expect(swap.Used + swap.Free).To(BeNumerically("<=", swap.Total))
 	})
 
-	It("GetSwap", func() {
+	It("GetFileSystemUsage", func() {
 		fsusage, err := concreteSigar.GetFileSystemUsage("/")
 		Expect(err).ToNot(HaveOccurred())
 		Expect(fsusage.Total).ToNot(BeNil())
@@ -96,4 +102,64 @@ var _ = Describe("ConcreteSigar", func() {
 		Expect(err).To(HaveOccurred())
 		Expect(fsusage.Total).To(Equal(uint64(0)))
 	})
+
+	It("GetCpuList", func() {
+		cpuList, err := concreteSigar.GetCpuList()
+		Expect(err).ToNot(HaveOccurred())
+		// Indexed by CPU id, so offline CPUs and those outside our cpuset are listed too
+		Expect(len(cpuList.List)).To(BeNumerically(">=", runtime.NumCPU()))
+	})
+
+	It("GetProcState", func() {
+		state, err := concreteSigar.GetProcState(os.Getpid())
+		if runtime.GOOS == "darwin" {
+			Expect(err).To(Equal(sigar.ErrNotImplemented))
+		} else {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(state.Pid).To(Equal(os.Getpid()))
+		}
+	})
+
+	It("GetProcFileSystemList", func() {
+		fsList, err := concreteSigar.GetProcFileSystemList(os.Getpid())
+		if runtime.GOOS == "linux" {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(len(fsList.List)).To(BeNumerically(">", 0))
+		} else {
+			Expect(sigar.IsNotImplemented(err)).To(BeTrue())
+		}
+	})
+
+	It("GetProcList", func() {
+		pids, err := concreteSigar.GetProcList()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pids.List).To(ContainElement(os.Getpid()))
+	})
+
+	It("gives up on list collectors when the context is done", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := concreteSigar.GetProcessListContext(ctx)
+		Expect(err).To(Equal(context.Canceled))
+		_, err = concreteSigar.GetFileSystemListContext(ctx)
+		Expect(err).To(Equal(context.Canceled))
+		_, err = concreteSigar.GetNetConnListContext(ctx)
+		Expect(err).To(Equal(context.Canceled))
+	})
+
+	It("is interchangeable with the fake", func() {
+		fake := fakes.NewFakeSigar()
+		fake.DiskList = sigar.DiskList{List: map[string]sigar.DiskIo{"sda": {ReadOps: 1}}}
+		fake.ProcStateErr = sigar.ErrNotImplemented
+
+		var s sigar.Sigar = fake
+		diskList, err := s.GetDiskList()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(diskList.List["sda"].ReadOps).To(Equal(uint64(1)))
+
+		_, err = s.GetProcState(10)
+		Expect(err).To(Equal(sigar.ErrNotImplemented))
+		Expect(fake.ProcStatePid).To(Equal(10))
+	})
 })
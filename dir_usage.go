@@ -0,0 +1,237 @@
+package sigar
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+type DirUsageOptions struct {
+	// Directories read at the same time. Defaults to 4.
+	Workers int
+
+	// Length of TopDirs and TopFiles. Defaults to 10.
+	Top int
+}
+
+// DirUsage is the disk usage of a directory tree, like du -x. The walk stays
+// on the filesystem of the root, and files with several hard links in the
+// tree are counted once.
+type DirUsage struct {
+	Path     string
+	Bytes    uint64          // Allocated on disk, so sparse files count what they use
+	Files    uint64          // Inodes, including directories and the root itself
+	TopDirs  []DirUsageEntry // Largest subdirectories by everything under them, largest first
+	TopFiles []DirUsageEntry // Largest files, largest first
+}
+
+type DirUsageEntry struct {
+	Path  string
+	Bytes uint64
+	Files uint64 // 1 for files
+}
+
+// Get walks path with the default options.
+func (self *DirUsage) Get(path string) error {
+	return self.GetContext(context.Background(), path, DirUsageOptions{})
+}
+
+// GetContext walks path until it is done or ctx is. Directories that can't be
+// read are left out, and returned in a *PartialError keyed by their path.
+func (self *DirUsage) GetContext(ctx context.Context, path string, options DirUsageOptions) error {
+	if options.Workers <= 0 {
+		options.Workers = 4
+	}
+	if options.Top <= 0 {
+		options.Top = 10
+	}
+
+	info, err := os.Lstat(path)
+	if err != nil {
+		return err
+	}
+	root, _, rootBytes := statFile(info)
+
+	walker := &dirWalker{
+		ctx:      ctx,
+		dev:      root.dev,
+		workers:  make(chan struct{}, options.Workers-1),
+		seen:     make(map[fileId]struct{}),
+		topDirs:  dirUsageTop{size: options.Top},
+		topFiles: dirUsageTop{size: options.Top},
+	}
+
+	usage := DirUsageEntry{Path: path, Bytes: rootBytes, Files: 1}
+	if info.IsDir() {
+		walker.seen[root] = struct{}{}
+		bytes, files := walker.walk(path)
+		usage.Bytes += bytes
+		usage.Files += files
+	}
+
+	if err := ctx.Err(); err != nil {
+		return contextError(err, "walk", path, 0)
+	}
+
+	self.Path = path
+	self.Bytes = usage.Bytes
+	self.Files = usage.Files
+	self.TopDirs = walker.topDirs.entries
+	self.TopFiles = walker.topFiles.entries
+	return walker.failed.orNil()
+}
+
+// Identifies a file for counting hard links once
+type fileId struct {
+	dev uint64
+	ino uint64
+}
+
+type dirWalker struct {
+	ctx     context.Context
+	dev     uint64
+	workers chan struct{} // Slots for walking subdirectories in another goroutine
+
+	sync.Mutex
+	seen     map[fileId]struct{} // Directories, and files with more than one link
+	topDirs  dirUsageTop
+	topFiles dirUsageTop
+	failed   PartialError
+}
+
+// Usage of everything under dir, excluding dir itself
+func (self *dirWalker) walk(dir string) (uint64, uint64) {
+	if self.ctx.Err() != nil {
+		return 0, 0
+	}
+
+	f, err := os.Open(dir)
+	if err != nil {
+		self.fail(dir, err)
+		return 0, 0
+	}
+	defer f.Close()
+
+	var bytes, files uint64
+	var wg sync.WaitGroup
+
+	// Totals of subdirectories, which may be walked in other goroutines
+	var subdirsLock sync.Mutex
+	var subdirsBytes, subdirsFiles uint64
+
+	for self.ctx.Err() == nil {
+		infos, err := f.Readdir(1024)
+		for _, info := range infos {
+			id, links, size := statFile(info)
+			if id.dev != self.dev {
+				continue // Another filesystem mounted below dir
+			}
+			name := filepath.Join(dir, info.Name())
+
+			if !info.IsDir() {
+				if links > 1 && !self.firstVisit(id) {
+					continue
+				}
+				bytes += size
+				files++
+				self.addFile(DirUsageEntry{Path: name, Bytes: size, Files: 1})
+				continue
+			}
+
+			// Bind mounts can bring a directory back into the tree
+			if !self.firstVisit(id) {
+				continue
+			}
+			subdir := func() {
+				subBytes, subFiles := self.walk(name)
+				subBytes += size
+				subFiles++
+				self.addDir(DirUsageEntry{Path: name, Bytes: subBytes, Files: subFiles})
+
+				subdirsLock.Lock()
+				subdirsBytes += subBytes
+				subdirsFiles += subFiles
+				subdirsLock.Unlock()
+			}
+
+			// Walk subdirectories in their own goroutine while there is a free
+			// worker, otherwise in this one
+			select {
+			case self.workers <- struct{}{}:
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					defer func() { <-self.workers }()
+					subdir()
+				}()
+			default:
+				subdir()
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			self.fail(dir, err)
+			break
+		}
+	}
+
+	wg.Wait()
+	return bytes + subdirsBytes, files + subdirsFiles
+}
+
+// True the first time id is seen, or always when the platform gives no ids
+func (self *dirWalker) firstVisit(id fileId) bool {
+	if id == (fileId{}) {
+		return true
+	}
+	self.Lock()
+	defer self.Unlock()
+	if _, ok := self.seen[id]; ok {
+		return false
+	}
+	self.seen[id] = struct{}{}
+	return true
+}
+
+func (self *dirWalker) addFile(entry DirUsageEntry) {
+	self.Lock()
+	self.topFiles.add(entry)
+	self.Unlock()
+}
+
+func (self *dirWalker) addDir(entry DirUsageEntry) {
+	self.Lock()
+	self.topDirs.add(entry)
+	self.Unlock()
+}
+
+func (self *dirWalker) fail(path string, err error) {
+	self.Lock()
+	self.failed.add(path, err)
+	self.Unlock()
+}
+
+// The largest entries added, largest first
+type dirUsageTop struct {
+	size    int
+	entries []DirUsageEntry
+}
+
+func (self *dirUsageTop) add(entry DirUsageEntry) {
+	i := len(self.entries)
+	for i > 0 && self.entries[i-1].Bytes < entry.Bytes {
+		i--
+	}
+	if i >= self.size {
+		return
+	}
+	if len(self.entries) < self.size {
+		self.entries = append(self.entries, DirUsageEntry{})
+	}
+	copy(self.entries[i+1:], self.entries[i:])
+	self.entries[i] = entry
+}
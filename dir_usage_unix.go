@@ -0,0 +1,17 @@
+// +build darwin freebsd linux netbsd openbsd
+
+package sigar
+
+import (
+	"os"
+	"syscall"
+)
+
+// Identity, link count and allocated bytes of a file from its lstat
+func statFile(info os.FileInfo) (fileId, uint64, uint64) {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return fileId{}, 1, uint64(info.Size())
+	}
+	return fileId{dev: uint64(stat.Dev), ino: uint64(stat.Ino)}, uint64(stat.Nlink), uint64(stat.Blocks) * 512
+}
@@ -0,0 +1,86 @@
+// +build darwin freebsd linux netbsd openbsd
+
+package sigar_test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/scalingdata/ginkgo"
+	. "github.com/scalingdata/gomega"
+
+	sigar "github.com/scalingdata/gosigar"
+)
+
+var _ = Describe("DirUsage", func() {
+	var root string
+
+	writeFile := func(name string, size int) {
+		path := filepath.Join(root, name)
+		Expect(os.MkdirAll(filepath.Dir(path), 0755)).To(Succeed())
+		Expect(ioutil.WriteFile(path, make([]byte, size), 0644)).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		var err error
+		root, err = ioutil.TempDir("", "sigarTests")
+		Expect(err).ToNot(HaveOccurred())
+
+		writeFile("logs/big", 1<<20)
+		writeFile("logs/old/small", 4096)
+		writeFile("data/medium", 256<<10)
+		writeFile("empty", 0)
+		Expect(os.Link(filepath.Join(root, "logs/big"), filepath.Join(root, "data/big-link"))).To(Succeed())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(root)
+	})
+
+	It("totals the tree, counting hard links once", func() {
+		usage := sigar.DirUsage{}
+		err := usage.GetContext(context.Background(), root, sigar.DirUsageOptions{Workers: 2, Top: 2})
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(usage.Path).To(Equal(root))
+		// The root, 3 directories and 4 files
+		Expect(usage.Files).To(Equal(uint64(8)))
+		Expect(usage.Bytes).To(BeNumerically(">=", (1<<20)+(256<<10)+4096))
+		Expect(usage.Bytes).To(BeNumerically("<", 2<<20))
+
+		Expect(usage.TopFiles).To(HaveLen(2))
+		Expect([]string{usage.TopFiles[0].Path, usage.TopFiles[1].Path}).To(ConsistOf(
+			ContainSubstring("big"), HaveSuffix("medium")))
+		Expect(usage.TopFiles[0].Bytes).To(BeNumerically(">=", usage.TopFiles[1].Bytes))
+
+		Expect(usage.TopDirs).To(HaveLen(2))
+		Expect(usage.TopDirs[0].Bytes).To(BeNumerically(">=", usage.TopDirs[1].Bytes))
+		// Whichever of them holds the file that has two links
+		Expect([]string{usage.TopDirs[0].Path, usage.TopDirs[1].Path}).To(ConsistOf(
+			filepath.Join(root, "logs"), filepath.Join(root, "data")))
+	})
+
+	It("stops when the context is cancelled", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		usage := sigar.DirUsage{}
+		err := usage.GetContext(ctx, root, sigar.DirUsageOptions{})
+		Expect(err).To(Equal(context.Canceled))
+	})
+
+	It("reports directories it can't read", func() {
+		if os.Geteuid() == 0 {
+			Skip("root can read every directory")
+		}
+		Expect(os.Chmod(filepath.Join(root, "logs/old"), 0)).To(Succeed())
+		defer os.Chmod(filepath.Join(root, "logs/old"), 0755)
+
+		usage := sigar.DirUsage{}
+		err := usage.Get(root)
+		Expect(sigar.IsPartial(err)).To(BeTrue())
+		Expect(usage.Files).To(Equal(uint64(7)))
+	})
+})
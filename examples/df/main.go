@@ -11,7 +11,7 @@ import (
 const output_format = "%-15s %4s %4s %5s %4s %-15s\n"
 
 func formatSize(size uint64) string {
-	return sigar.FormatSize(size * 1024)
+	return sigar.FormatSize(size)
 }
 
 func main() {
@@ -22,6 +22,9 @@ func main() {
 		"Filesystem", "Size", "Used", "Avail", "Use%", "Mounted on")
 
 	for _, fs := range fslist.List {
+		if fs.Class == sigar.FileSystemPseudo {
+			continue
+		}
 		dir_name := fs.DirName
 
 		usage := sigar.FileSystemUsage{}
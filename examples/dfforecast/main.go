@@ -0,0 +1,72 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/scalingdata/gosigar"
+	"os"
+	"time"
+)
+
+const output_format = "%-15s %4s %4s %5s %4s %15s %15s %-15s\n"
+
+func formatDays(d time.Duration) string {
+	if d == sigar.ForecastNever {
+		return "-"
+	}
+	return fmt.Sprintf("%.1f", d.Hours()/24)
+}
+
+func main() {
+	interval := flag.Duration("interval", 10*time.Second, "time between samples")
+	samples := flag.Int("samples", 6, "number of samples to take")
+	flag.Parse()
+
+	forecaster := sigar.NewFileSystemForecaster(sigar.ForecastOptions{Resolution: *interval / 2})
+	fslist := sigar.FileSystemList{}
+	usages := map[string]sigar.FileSystemUsage{}
+
+	for i := 0; i < *samples; i++ {
+		if i > 0 {
+			time.Sleep(*interval)
+		}
+		fslist.Get()
+		now := time.Now()
+		for _, fs := range fslist.List {
+			if fs.Class == sigar.FileSystemPseudo {
+				continue
+			}
+			usage := sigar.FileSystemUsage{}
+			if err := usage.Get(fs.DirName); err != nil {
+				continue
+			}
+			usages[fs.DirName] = usage
+			forecaster.Add(fs.DirName, now, usage)
+		}
+	}
+
+	fmt.Fprintf(os.Stdout, output_format,
+		"Filesystem", "Size", "Used", "Avail", "Use%", "Days until full", "Inodes (days)", "Mounted on")
+
+	for _, fs := range fslist.List {
+		usage, ok := usages[fs.DirName]
+		if !ok {
+			continue
+		}
+		bytesFull, filesFull := "?", "?"
+		if forecast, ok := forecaster.Forecast(fs.DirName); ok {
+			bytesFull = formatDays(forecast.Bytes.TimeToFull)
+			filesFull = formatDays(forecast.Files.TimeToFull)
+		}
+
+		fmt.Fprintf(os.Stdout, output_format,
+			fs.DevName,
+			sigar.FormatSize(usage.Total),
+			sigar.FormatSize(usage.Used),
+			sigar.FormatSize(usage.Avail),
+			sigar.FormatPercent(usage.UsePercent()),
+			bytesFull,
+			filesFull,
+			fs.DirName)
+	}
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"github.com/scalingdata/gosigar"
+	"os"
+	"time"
+)
+
+const output_format = "%5s %9s %s\n"
+
+func printEntries(title string, entries []sigar.DirUsageEntry) {
+	fmt.Fprintf(os.Stdout, "\n%s\n", title)
+	fmt.Fprintf(os.Stdout, output_format, "Size", "Inodes", "Path")
+	for _, entry := range entries {
+		fmt.Fprintf(os.Stdout, output_format,
+			sigar.FormatSize(entry.Bytes),
+			fmt.Sprint(entry.Files),
+			entry.Path)
+	}
+}
+
+func main() {
+	top := flag.Int("top", 10, "number of largest directories and files to show")
+	workers := flag.Int("workers", 4, "directories to read at the same time")
+	timeout := flag.Duration("timeout", 0, "give up after this long, 0 for never")
+	flag.Parse()
+
+	path := "."
+	if flag.NArg() > 0 {
+		path = flag.Arg(0)
+	}
+
+	ctx := context.Background()
+	if *timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	usage := sigar.DirUsage{}
+	err := usage.GetContext(ctx, path, sigar.DirUsageOptions{Workers: *workers, Top: *top})
+	if err != nil && !sigar.IsPartial(err) {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	fmt.Fprintf(os.Stdout, "%s: %s in %d inodes, walked in %v\n",
+		usage.Path, sigar.FormatSize(usage.Bytes), usage.Files, time.Since(start))
+	printEntries("Largest directories", usage.TopDirs)
+	printEntries("Largest files", usage.TopFiles)
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	}
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/scalingdata/gosigar"
+	"os"
+)
+
+const output_format = "%-15s %6s %5s %-10s %10s %s\n"
+
+// The access mode, as lsof shows it after the fd number
+func accessMode(fd sigar.ProcFdEntry) string {
+	switch int(fd.Flags) & (os.O_WRONLY | os.O_RDWR) {
+	case os.O_WRONLY:
+		return "w"
+	case os.O_RDWR:
+		return "u"
+	}
+	return "r"
+}
+
+func name(fd sigar.ProcFdEntry) string {
+	if fd.Socket != nil {
+		conn := *fd.Socket
+		conn.Pid = 0
+		return conn.String()
+	}
+	return fd.Path
+}
+
+func main() {
+	pid := flag.Int("p", 0, "only list the files of this pid")
+	flag.Parse()
+
+	pids := sigar.ProcList{}
+	if *pid != 0 {
+		pids.List = []int{*pid}
+	} else {
+		pids.Get()
+	}
+
+	fmt.Fprintf(os.Stdout, output_format, "COMMAND", "PID", "FD", "TYPE", "OFFSET", "NAME")
+
+	for _, pid := range pids.List {
+		state := sigar.ProcState{}
+		if err := state.Get(pid); err != nil {
+			continue
+		}
+		fdList := sigar.ProcFdList{}
+		if err := fdList.Get(pid); err != nil {
+			continue
+		}
+
+		for _, fd := range fdList.List {
+			offset := ""
+			if fd.Type == sigar.FdFile {
+				offset = fmt.Sprint(fd.Pos)
+			}
+			fmt.Fprintf(os.Stdout, output_format,
+				state.Name,
+				fmt.Sprint(pid),
+				fmt.Sprintf("%d%s", fd.Fd, accessMode(fd)),
+				fd.Type,
+				offset,
+				name(fd))
+		}
+	}
+}
@@ -0,0 +1,942 @@
+// Package prometheus exposes gosigar metrics in the Prometheus text
+// exposition format, using node_exporter names, units and labels where
+// node_exporter has an equivalent metric and a sigar_ prefix otherwise.
+package prometheus
+
+import (
+	"context"
+	"io"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	sigar "github.com/scalingdata/gosigar"
+)
+
+// CPU and process times are reported by gosigar in USER_HZ ticks
+const ticksPerSecond = 100
+
+// Filesystem types skipped by default, as in node_exporter
+var DefaultIgnoredFSTypes = []string{
+	"autofs", "binfmt_misc", "bpf", "cgroup", "cgroup2", "configfs", "debugfs",
+	"devpts", "devtmpfs", "fusectl", "hugetlbfs", "iso9660", "mqueue", "nsfs",
+	"overlay", "proc", "procfs", "pstore", "rpc_pipefs", "securityfs", "selinuxfs",
+	"squashfs", "sysfs", "tracefs",
+}
+
+type Options struct {
+	// Export per-process metrics. Off by default, as every pid is a new series.
+	Processes bool
+
+	// Filesystem types to skip. Defaults to DefaultIgnoredFSTypes when nil.
+	IgnoredFSTypes []string
+
+	// How long to wait for statfs on each mount before giving up on it, when
+	// the Sigar supports it. Defaults to 5 seconds.
+	FileSystemTimeout time.Duration
+
+	// Export sigar_processes_near_fd_limit, and with Processes the open fds of
+	// each process. Off by default, as it lists the fds of every process.
+	ProcessFds bool
+
+	// Fraction of its soft open files limit past which a process is counted in
+	// sigar_processes_near_fd_limit. Defaults to 0.8.
+	FdLimitFraction float64
+
+	// Where failed collectors, and metrics ServeHTTP failed to send, are
+	// logged. Defaults to the standard logger.
+	ErrorLog *log.Logger
+}
+
+// Exporter writes the metrics of every gosigar collector. It implements
+// http.Handler, so it can be served directly as a /metrics endpoint.
+type Exporter struct {
+	sigar   sigar.Sigar
+	options Options
+	ignored map[string]bool
+}
+
+// Implemented by sigar.ConcreteSigar and sigar.LinuxSigar, so that a hung
+// mount doesn't hang the scrape.
+type fileSystemUsageContexter interface {
+	GetFileSystemUsageContext(ctx context.Context, path string) (sigar.FileSystemUsage, error)
+}
+
+func New(s sigar.Sigar, options Options) *Exporter {
+	if options.IgnoredFSTypes == nil {
+		options.IgnoredFSTypes = DefaultIgnoredFSTypes
+	}
+	if options.FileSystemTimeout == 0 {
+		options.FileSystemTimeout = 5 * time.Second
+	}
+	if options.FdLimitFraction <= 0 {
+		options.FdLimitFraction = 0.8
+	}
+
+	ignored := make(map[string]bool)
+	for _, fsType := range options.IgnoredFSTypes {
+		ignored[fsType] = true
+	}
+
+	return &Exporter{
+		sigar:   s,
+		options: options,
+		ignored: ignored,
+	}
+}
+
+// Write collects every metric and writes it in the text exposition format.
+// Collectors that fail are reported through sigar_scrape_collector_success.
+func (self *Exporter) Write(w io.Writer) error {
+	m := newMetrics()
+
+	collectors := []struct {
+		name    string
+		collect func(*metrics) error
+	}{
+		{"cpu", self.collectCpu},
+		{"cpuinfo", self.collectCpuInfo},
+		{"loadavg", self.collectLoadAverage},
+		{"uptime", self.collectUptime},
+		{"stat", self.collectKernelStat},
+		{"pressure", self.collectPressure},
+		{"meminfo", self.collectMem},
+		{"vmstat", self.collectVmStat},
+		{"swaps", self.collectSwapList},
+		{"numa", self.collectNumaNodes},
+		{"filesystem", self.collectFileSystems},
+		{"diskstats", self.collectDisks},
+		{"blockdevices", self.collectBlockDevices},
+		{"mdadm", self.collectMdArrays},
+		{"netdev", self.collectNetIfaces},
+		{"netstat", self.collectNetProtoV4},
+		{"netstat6", self.collectNetProtoV6},
+		{"connections", self.collectConnections},
+		{"processes", self.collectProcesses},
+		{"filefd", self.collectSystemFd},
+		{"uname", self.collectSystemInfo},
+		{"os", self.collectSystemDistribution},
+	}
+
+	for _, c := range collectors {
+		start := time.Now()
+		err := c.collect(m)
+		if sigar.IsNotImplemented(err) || sigar.IsNotSupported(err) {
+			continue
+		}
+
+		success := 1.0
+		if err != nil {
+			success = 0
+			self.logf("Error collecting %s: %s", c.name, err)
+		}
+		m.add("sigar_scrape_collector_success", gauge,
+			"Whether a collector succeeded.", success, "collector", c.name)
+		m.add("sigar_scrape_collector_duration_seconds", gauge,
+			"Duration of a collector scrape.", time.Since(start).Seconds(), "collector", c.name)
+	}
+
+	return m.write(w)
+}
+
+func (self *Exporter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", ContentType)
+	if err := self.Write(w); err != nil {
+		// e.g. the scraper timed out and hung up
+		self.logf("Error sending metrics: %s", err)
+	}
+}
+
+func (self *Exporter) logf(format string, args ...interface{}) {
+	if self.options.ErrorLog != nil {
+		self.options.ErrorLog.Printf(format, args...)
+	} else {
+		log.Printf(format, args...)
+	}
+}
+
+func (self *Exporter) collectCpu(m *metrics) error {
+	cpuList, err := self.sigar.GetCpuList()
+	if err != nil {
+		return err
+	}
+
+	for i, cpu := range cpuList.List {
+		// Offline
+		if cpu.Total() == 0 {
+			continue
+		}
+
+		id := strconv.Itoa(i)
+		modes := []struct {
+			mode  string
+			ticks uint64
+		}{
+			{"user", cpu.User},
+			{"nice", cpu.Nice},
+			{"system", cpu.Sys},
+			{"idle", cpu.Idle},
+			{"iowait", cpu.Wait},
+			{"irq", cpu.Irq},
+			{"softirq", cpu.SoftIrq},
+			{"steal", cpu.Stolen},
+		}
+		for _, mode := range modes {
+			m.add("node_cpu_seconds_total", counter,
+				"Seconds the CPUs spent in each mode.",
+				float64(mode.ticks)/ticksPerSecond, "cpu", id, "mode", mode.mode)
+		}
+		m.add("node_cpu_guest_seconds_total", counter,
+			"Seconds the CPUs spent in guests (VMs) for each mode.",
+			float64(cpu.Guest)/ticksPerSecond, "cpu", id, "mode", "user")
+		m.add("node_cpu_guest_seconds_total", counter,
+			"Seconds the CPUs spent in guests (VMs) for each mode.",
+			float64(cpu.GuestNice)/ticksPerSecond, "cpu", id, "mode", "nice")
+	}
+	return nil
+}
+
+func (self *Exporter) collectLoadAverage(m *metrics) error {
+	load, err := self.sigar.GetLoadAverage()
+	if err != nil {
+		return err
+	}
+
+	m.add("node_load1", gauge, "1m load average.", load.One)
+	m.add("node_load5", gauge, "5m load average.", load.Five)
+	m.add("node_load15", gauge, "15m load average.", load.Fifteen)
+	return nil
+}
+
+func (self *Exporter) collectUptime(m *metrics) error {
+	uptime, err := self.sigar.GetUptime()
+	if err != nil {
+		return err
+	}
+
+	bootTime := float64(time.Now().Unix()) - uptime.Length
+	m.add("node_boot_time_seconds", gauge, "Node boot time, in unixtime.", bootTime)
+	return nil
+}
+
+func (self *Exporter) collectKernelStat(m *metrics) error {
+	stat, err := self.sigar.GetKernelStat()
+	if err != nil {
+		return err
+	}
+
+	m.add("node_context_switches_total", counter, "Total number of context switches.", float64(stat.ContextSwitches))
+	m.add("node_intr_total", counter, "Total number of interrupts serviced.", float64(stat.Interrupts))
+	m.add("node_softirqs_total", counter, "Total number of softirqs serviced.", float64(stat.SoftIrqs))
+	m.add("node_forks_total", counter, "Total number of forks.", float64(stat.Forks))
+	m.add("node_procs_running", gauge, "Number of processes in runnable state.", float64(stat.ProcsRunning))
+	m.add("node_procs_blocked", gauge, "Number of processes blocked waiting for I/O to complete.", float64(stat.ProcsBlocked))
+	return nil
+}
+
+func (self *Exporter) collectPressure(m *metrics) error {
+	pressure, err := self.sigar.GetPressure()
+	if err != nil {
+		return err
+	}
+
+	// PSI totals are in microseconds
+	m.add("node_pressure_cpu_waiting_seconds_total", counter,
+		"Total time in seconds that processes have waited for CPU time.", float64(pressure.Cpu.Some.Total)/1e6)
+	m.add("node_pressure_memory_waiting_seconds_total", counter,
+		"Total time in seconds that processes have waited for memory.", float64(pressure.Memory.Some.Total)/1e6)
+	m.add("node_pressure_memory_stalled_seconds_total", counter,
+		"Total time in seconds no process could make progress due to memory congestion.", float64(pressure.Memory.Full.Total)/1e6)
+	m.add("node_pressure_io_waiting_seconds_total", counter,
+		"Total time in seconds that processes have waited due to IO congestion.", float64(pressure.Io.Some.Total)/1e6)
+	m.add("node_pressure_io_stalled_seconds_total", counter,
+		"Total time in seconds no process could make progress due to IO congestion.", float64(pressure.Io.Full.Total)/1e6)
+	return nil
+}
+
+func (self *Exporter) collectMem(m *metrics) error {
+	info, err := self.sigar.GetMemInfo()
+	if sigar.IsNotImplemented(err) {
+		return self.collectMemFallback(m)
+	}
+	if err != nil {
+		return err
+	}
+
+	// Named after the /proc/meminfo keys, as node_exporter does
+	values := []struct {
+		key   string
+		value uint64
+	}{
+		{"MemTotal", info.MemTotal},
+		{"MemFree", info.MemFree},
+		{"MemAvailable", info.MemAvailable},
+		{"Buffers", info.Buffers},
+		{"Cached", info.Cached},
+		{"SwapCached", info.SwapCached},
+		{"Active", info.Active},
+		{"Inactive", info.Inactive},
+		{"Active_anon", info.ActiveAnon},
+		{"Inactive_anon", info.InactiveAnon},
+		{"Active_file", info.ActiveFile},
+		{"Inactive_file", info.InactiveFile},
+		{"Unevictable", info.Unevictable},
+		{"Mlocked", info.Mlocked},
+		{"SwapTotal", info.SwapTotal},
+		{"SwapFree", info.SwapFree},
+		{"Dirty", info.Dirty},
+		{"Writeback", info.Writeback},
+		{"AnonPages", info.AnonPages},
+		{"Mapped", info.Mapped},
+		{"Shmem", info.Shmem},
+		{"KReclaimable", info.KReclaimable},
+		{"Slab", info.Slab},
+		{"SReclaimable", info.SReclaimable},
+		{"SUnreclaim", info.SUnreclaim},
+		{"KernelStack", info.KernelStack},
+		{"PageTables", info.PageTables},
+		{"NFS_Unstable", info.NFSUnstable},
+		{"Bounce", info.Bounce},
+		{"WritebackTmp", info.WritebackTmp},
+		{"CommitLimit", info.CommitLimit},
+		{"Committed_AS", info.CommittedAS},
+		{"VmallocTotal", info.VmallocTotal},
+		{"VmallocUsed", info.VmallocUsed},
+		{"VmallocChunk", info.VmallocChunk},
+		{"Percpu", info.Percpu},
+		{"HardwareCorrupted", info.HardwareCorrupted},
+		{"AnonHugePages", info.AnonHugePages},
+		{"ShmemHugePages", info.ShmemHugePages},
+		{"ShmemPmdMapped", info.ShmemPmdMapped},
+		{"FileHugePages", info.FileHugePages},
+		{"FilePmdMapped", info.FilePmdMapped},
+		{"CmaTotal", info.CmaTotal},
+		{"CmaFree", info.CmaFree},
+		{"HugePages_Total", info.HugePagesTotal},
+		{"HugePages_Free", info.HugePagesFree},
+		{"HugePages_Rsvd", info.HugePagesRsvd},
+		{"HugePages_Surp", info.HugePagesSurp},
+		{"Hugepagesize", info.Hugepagesize},
+		{"Hugetlb", info.Hugetlb},
+		{"DirectMap4k", info.DirectMap4k},
+		{"DirectMap2M", info.DirectMap2M},
+		{"DirectMap1G", info.DirectMap1G},
+	}
+	for _, v := range values {
+		// Kernels before 3.14 have no MemAvailable, and a zero would read
+		// as no memory available at all
+		if v.key == "MemAvailable" && !info.HasMemAvailable {
+			continue
+		}
+		addMemInfo(m, v.key, v.value)
+	}
+
+	keys := make([]string, 0, len(info.Other))
+	for key := range info.Other {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		addMemInfo(m, strings.NewReplacer("(", "_", ")", "").Replace(key), info.Other[key])
+	}
+	return nil
+}
+
+func addMemInfo(m *metrics, key string, value uint64) {
+	// HugePages_* are page counts
+	if !strings.HasPrefix(key, "HugePages_") {
+		key += "_bytes"
+	}
+	m.add("node_memory_"+key, gauge, "Memory information field "+key+".", float64(value))
+}
+
+func (self *Exporter) collectVmStat(m *metrics) error {
+	vmstat, err := self.sigar.GetVmStat()
+	if err != nil {
+		return err
+	}
+
+	// Named after the /proc/vmstat keys, as node_exporter does
+	values := []struct {
+		key   string
+		value uint64
+	}{
+		{"pgpgin", vmstat.PgpgIn},
+		{"pgpgout", vmstat.PgpgOut},
+		{"pswpin", vmstat.PswpIn},
+		{"pswpout", vmstat.PswpOut},
+		{"pgfault", vmstat.PgFault},
+		{"pgmajfault", vmstat.PgMajFault},
+		{"pgscan_kswapd", vmstat.PgScanKswapd},
+		{"pgscan_direct", vmstat.PgScanDirect},
+		{"pgsteal_kswapd", vmstat.PgStealKswapd},
+		{"pgsteal_direct", vmstat.PgStealDirect},
+		{"oom_kill", vmstat.OomKill},
+		{"thp_fault_alloc", vmstat.ThpFaultAlloc},
+		{"thp_fault_fallback", vmstat.ThpFaultFallback},
+		{"thp_collapse_alloc", vmstat.ThpCollapseAlloc},
+		{"thp_collapse_alloc_failed", vmstat.ThpCollapseAllocFailed},
+		{"thp_split_page", vmstat.ThpSplitPage},
+	}
+
+	keys := make([]string, 0, len(vmstat.Other))
+	for key := range vmstat.Other {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		values = append(values, struct {
+			key   string
+			value uint64
+		}{key, vmstat.Other[key]})
+	}
+
+	for _, v := range values {
+		typ := counter
+		if strings.HasPrefix(v.key, "nr_") {
+			typ = gauge
+		}
+		m.add("node_vmstat_"+v.key, typ, "/proc/vmstat information field "+v.key+".", float64(v.value))
+	}
+	return nil
+}
+
+func (self *Exporter) collectSwapList(m *metrics) error {
+	swaps, err := self.sigar.GetSwapList()
+	if err != nil {
+		return err
+	}
+
+	for _, swap := range swaps.List {
+		labels := []string{"device", swap.Name, "type", swap.Type}
+		m.add("sigar_swap_size_bytes", gauge, "Size of the swap device in bytes.", float64(swap.Size), labels...)
+		m.add("sigar_swap_used_bytes", gauge, "Swap space used on the device in bytes.", float64(swap.Used), labels...)
+		m.add("sigar_swap_priority", gauge, "Priority of the swap device.", float64(swap.Priority), labels...)
+
+		if !swap.IsZram {
+			continue
+		}
+		m.add("sigar_zram_orig_data_bytes", gauge, "Bytes stored in the zram device, before compression.",
+			float64(swap.Zram.OrigDataSize), "device", swap.Name)
+		m.add("sigar_zram_compr_data_bytes", gauge, "Bytes stored in the zram device, after compression.",
+			float64(swap.Zram.ComprDataSize), "device", swap.Name)
+		m.add("sigar_zram_mem_used_bytes", gauge, "Bytes of RAM used by the zram device.",
+			float64(swap.Zram.MemUsedTotal), "device", swap.Name)
+	}
+	return nil
+}
+
+func (self *Exporter) collectCpuInfo(m *metrics) error {
+	cpuInfo, err := self.sigar.GetCpuInfo()
+	if err != nil {
+		return err
+	}
+
+	for _, cpu := range cpuInfo.List {
+		id := strconv.Itoa(cpu.Id)
+		online := 0.0
+		if cpu.Online {
+			online = 1
+		}
+		m.add("node_cpu_online", gauge, "Whether the CPU is online.", online, "cpu", id)
+		if !cpu.Online {
+			continue
+		}
+
+		m.add("node_cpu_info", gauge, "CPU information.", 1,
+			"cpu", id, "vendor", cpu.Vendor, "model_name", cpu.ModelName,
+			"package", strconv.Itoa(cpu.SocketId), "core", strconv.Itoa(cpu.CoreId))
+		if cpu.CurFreq > 0 {
+			m.add("node_cpu_scaling_frequency_hertz", gauge, "Current CPU frequency.", float64(cpu.CurFreq), "cpu", id)
+		}
+		if cpu.MinFreq > 0 {
+			m.add("node_cpu_frequency_min_hertz", gauge, "Minimum CPU frequency.", float64(cpu.MinFreq), "cpu", id)
+		}
+		if cpu.MaxFreq > 0 {
+			m.add("node_cpu_frequency_max_hertz", gauge, "Maximum CPU frequency.", float64(cpu.MaxFreq), "cpu", id)
+		}
+		if cpu.Governor != "" {
+			m.add("node_cpu_scaling_governor", gauge, "Frequency scaling governor of the CPU.", 1,
+				"cpu", id, "governor", cpu.Governor)
+		}
+	}
+	return nil
+}
+
+func (self *Exporter) collectNumaNodes(m *metrics) error {
+	nodes, err := self.sigar.GetNumaNodeList()
+	if err != nil {
+		return err
+	}
+
+	for _, node := range nodes.List {
+		id := strconv.Itoa(node.Id)
+		m.add("node_memory_numa_MemTotal", gauge, "Memory of the NUMA node in bytes.", float64(node.MemTotal), "node", id)
+		m.add("node_memory_numa_MemFree", gauge, "Free memory of the NUMA node in bytes.", float64(node.MemFree), "node", id)
+		m.add("node_memory_numa_MemUsed", gauge, "Used memory of the NUMA node in bytes.", float64(node.MemUsed), "node", id)
+
+		stats := []struct {
+			name  string
+			value uint64
+		}{
+			{"numa_hit", node.NumaStat.NumaHit},
+			{"numa_miss", node.NumaStat.NumaMiss},
+			{"numa_foreign", node.NumaStat.NumaForeign},
+			{"interleave_hit", node.NumaStat.InterleaveHit},
+			{"local_node", node.NumaStat.LocalNode},
+			{"other_node", node.NumaStat.OtherNode},
+		}
+		for _, stat := range stats {
+			m.add("node_memory_numa_"+stat.name+"_total", counter, "NUMA node page allocations: "+stat.name+".",
+				float64(stat.value), "node", id)
+		}
+
+		for _, cpu := range node.Cpus {
+			m.add("sigar_numa_node_cpu_info", gauge, "CPUs attached to the NUMA node.", 1,
+				"node", id, "cpu", strconv.Itoa(cpu))
+		}
+		for i, distance := range node.Distances {
+			if i >= len(nodes.List) {
+				break
+			}
+			m.add("sigar_numa_node_distance", gauge, "Relative distance between two NUMA nodes.", float64(distance),
+				"node", id, "to", strconv.Itoa(nodes.List[i].Id))
+		}
+	}
+	return nil
+}
+
+// For platforms without MemInfo
+func (self *Exporter) collectMemFallback(m *metrics) error {
+	mem, err := self.sigar.GetMem()
+	if err != nil {
+		return err
+	}
+	swap, err := self.sigar.GetSwap()
+	if err != nil {
+		return err
+	}
+
+	addMemInfo(m, "MemTotal", mem.Total)
+	addMemInfo(m, "MemFree", mem.Free)
+	addMemInfo(m, "MemAvailable", mem.ActualFree)
+	addMemInfo(m, "SwapTotal", swap.Total)
+	addMemInfo(m, "SwapFree", swap.Free)
+	return nil
+}
+
+func (self *Exporter) collectFileSystems(m *metrics) error {
+	fsList, err := self.sigar.GetFileSystemList()
+	if err != nil {
+		return err
+	}
+
+	var firstErr error
+	seen := make(map[string]bool)
+	for _, fs := range fsList.List {
+		if self.ignored[fs.SysTypeName] || seen[fs.DirName] {
+			continue
+		}
+		seen[fs.DirName] = true
+
+		labels := []string{"device", fs.DevName, "fstype", fs.SysTypeName, "mountpoint", fs.DirName}
+
+		usage, err := self.fileSystemUsage(fs.DirName)
+		deviceError := 0.0
+		if err != nil {
+			deviceError = 1
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+		m.add("node_filesystem_device_error", gauge,
+			"Whether an error occurred while getting statistics for the given device.", deviceError, labels...)
+		if err != nil {
+			continue
+		}
+
+		readOnly := 0.0
+		if usage.ReadOnly {
+			readOnly = 1
+		}
+		m.add("node_filesystem_size_bytes", gauge, "Filesystem size in bytes.", float64(usage.Total), labels...)
+		m.add("node_filesystem_free_bytes", gauge, "Filesystem free space in bytes.", float64(usage.Free), labels...)
+		m.add("node_filesystem_avail_bytes", gauge, "Filesystem space available to non-root users in bytes.", float64(usage.Avail), labels...)
+		m.add("node_filesystem_readonly", gauge, "Filesystem read-only status.", readOnly, labels...)
+		m.add("node_filesystem_files", gauge, "Filesystem total file nodes.", float64(usage.Files), labels...)
+		m.add("node_filesystem_files_free", gauge, "Filesystem total free file nodes.", float64(usage.FreeFiles), labels...)
+	}
+	return firstErr
+}
+
+func (self *Exporter) fileSystemUsage(path string) (sigar.FileSystemUsage, error) {
+	if s, ok := self.sigar.(fileSystemUsageContexter); ok {
+		ctx, cancel := context.WithTimeout(context.Background(), self.options.FileSystemTimeout)
+		defer cancel()
+		return s.GetFileSystemUsageContext(ctx, path)
+	}
+	return self.sigar.GetFileSystemUsage(path)
+}
+
+func (self *Exporter) collectDisks(m *metrics) error {
+	disks, err := self.sigar.GetDiskList()
+	if disks.List == nil {
+		return err
+	}
+
+	names := make([]string, 0, len(disks.List))
+	for name := range disks.List {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		disk := disks.List[name]
+		m.add("node_disk_reads_completed_total", counter, "The total number of reads completed successfully.", float64(disk.ReadOps), "device", name)
+		m.add("node_disk_read_bytes_total", counter, "The total number of bytes read successfully.", float64(disk.ReadBytes), "device", name)
+		m.add("node_disk_read_time_seconds_total", counter, "The total number of seconds spent by all reads.", float64(disk.ReadTimeMs)/1000, "device", name)
+		m.add("node_disk_writes_completed_total", counter, "The total number of writes completed successfully.", float64(disk.WriteOps), "device", name)
+		m.add("node_disk_written_bytes_total", counter, "The total number of bytes written successfully.", float64(disk.WriteBytes), "device", name)
+		m.add("node_disk_write_time_seconds_total", counter, "This is the total number of seconds spent by all writes.", float64(disk.WriteTimeMs)/1000, "device", name)
+		m.add("node_disk_io_time_seconds_total", counter, "Total seconds spent doing I/Os.", float64(disk.IoTimeMs)/1000, "device", name)
+		m.add("node_disk_reads_merged_total", counter, "The total number of reads merged.", float64(disk.ReadMerged), "device", name)
+		m.add("node_disk_writes_merged_total", counter, "The number of writes merged.", float64(disk.WriteMerged), "device", name)
+		m.add("node_disk_io_now", gauge, "The number of I/Os currently in progress.", float64(disk.IoInProgress), "device", name)
+		m.add("node_disk_io_time_weighted_seconds_total", counter, "The weighted # of seconds spent doing I/Os.", float64(disk.WeightedIoTimeMs)/1000, "device", name)
+		m.add("node_disk_discards_completed_total", counter, "The total number of discards completed successfully.", float64(disk.DiscardOps), "device", name)
+		m.add("node_disk_discards_merged_total", counter, "The total number of discards merged.", float64(disk.DiscardMerged), "device", name)
+		m.add("node_disk_discarded_sectors_total", counter, "The total number of sectors discarded successfully.", float64(disk.DiscardBytes/512), "device", name)
+		m.add("node_disk_discard_time_seconds_total", counter, "This is the total number of seconds spent by all discards.", float64(disk.DiscardTimeMs)/1000, "device", name)
+		m.add("node_disk_flush_requests_total", counter, "The total number of flush requests completed successfully.", float64(disk.FlushOps), "device", name)
+		m.add("node_disk_flush_requests_time_seconds_total", counter, "This is the total number of seconds spent by all flush requests.", float64(disk.FlushTimeMs)/1000, "device", name)
+	}
+	return err
+}
+
+func (self *Exporter) collectBlockDevices(m *metrics) error {
+	devices, err := self.sigar.GetBlockDeviceList()
+	if err != nil {
+		return err
+	}
+
+	for _, device := range devices.List {
+		rotational := "0"
+		if device.Rotational {
+			rotational = "1"
+		}
+		m.add("sigar_block_device_info", gauge, "Block device information.", 1,
+			"device", device.Name, "parent", device.Parent, "model", device.Model, "serial", device.Serial,
+			"rotational", rotational, "scheduler", device.Scheduler, "mapper_name", device.MapperName)
+		m.add("sigar_block_device_size_bytes", gauge, "Size of the block device in bytes.", float64(device.Size),
+			"device", device.Name)
+	}
+	return nil
+}
+
+func (self *Exporter) collectMdArrays(m *metrics) error {
+	arrays, err := self.sigar.GetMdArrayList()
+	if err != nil {
+		return err
+	}
+
+	for _, array := range arrays.List {
+		active := 0.0
+		if array.Active {
+			active = 1
+		}
+		synced := array.Size
+		if array.SyncAction != "idle" {
+			synced = uint64(float64(array.Size) * array.SyncCompleted)
+		}
+
+		m.add("node_md_state", gauge, "Indicates the state of md-device.", active, "device", array.Name, "state", array.State)
+		m.add("node_md_disks_required", gauge, "Total number of disks of device.", float64(array.RaidDisks), "device", array.Name)
+		m.add("node_md_disks", gauge, "Number of active/failed/spare disks of device.", float64(array.ActiveDisks), "device", array.Name, "state", "active")
+		m.add("node_md_disks", gauge, "Number of active/failed/spare disks of device.", float64(array.Failed), "device", array.Name, "state", "failed")
+		m.add("node_md_disks", gauge, "Number of active/failed/spare disks of device.", float64(array.Spares), "device", array.Name, "state", "spare")
+		m.add("sigar_md_degraded", gauge, "Number of missing disks of device.", float64(array.Degraded), "device", array.Name)
+		m.add("node_md_blocks", gauge, "Total number of blocks on device.", float64(array.Size/1024), "device", array.Name)
+		m.add("node_md_blocks_synced", gauge, "Number of blocks synced on device.", float64(synced/1024), "device", array.Name)
+		m.add("sigar_md_sync_eta_seconds", gauge, "Estimated time until the sync of the device completes.", array.SyncEta.Seconds(), "device", array.Name)
+	}
+	return nil
+}
+
+func (self *Exporter) collectNetIfaces(m *metrics) error {
+	ifaces, err := self.sigar.GetNetIfaceList()
+	if err != nil {
+		return err
+	}
+
+	for _, iface := range ifaces.List {
+		counters := []struct {
+			name  string
+			help  string
+			value uint64
+		}{
+			{"receive_bytes", "Network device statistic receive_bytes.", iface.RecvBytes},
+			{"receive_packets", "Network device statistic receive_packets.", iface.RecvPackets},
+			{"receive_errs", "Network device statistic receive_errs.", iface.RecvErrors},
+			{"receive_drop", "Network device statistic receive_drop.", iface.RecvDropped},
+			{"receive_fifo", "Network device statistic receive_fifo.", iface.RecvFifoErrors},
+			{"receive_frame", "Network device statistic receive_frame.", iface.RecvFramingErrors},
+			{"receive_compressed", "Network device statistic receive_compressed.", iface.RecvCompressed},
+			{"receive_multicast", "Network device statistic receive_multicast.", iface.RecvMulticast},
+			{"transmit_bytes", "Network device statistic transmit_bytes.", iface.SendBytes},
+			{"transmit_packets", "Network device statistic transmit_packets.", iface.SendPackets},
+			{"transmit_errs", "Network device statistic transmit_errs.", iface.SendErrors},
+			{"transmit_drop", "Network device statistic transmit_drop.", iface.SendDropped},
+			{"transmit_fifo", "Network device statistic transmit_fifo.", iface.SendFifoErrors},
+			{"transmit_colls", "Network device statistic transmit_colls.", iface.SendCollisions},
+			{"transmit_carrier", "Network device statistic transmit_carrier.", iface.SendCarrier},
+			{"transmit_compressed", "Network device statistic transmit_compressed.", iface.SendCompressed},
+		}
+		for _, c := range counters {
+			m.add("node_network_"+c.name+"_total", counter, c.help, float64(c.value), "device", iface.Name)
+		}
+
+		m.add("node_network_mtu_bytes", gauge, "Network device property mtu_bytes.", float64(iface.MTU), "device", iface.Name)
+
+		up := 0.0
+		if iface.LinkStatus == "UP" {
+			up = 1
+		}
+		m.add("node_network_up", gauge, "Whether the network interface has carrier.", up, "device", iface.Name)
+	}
+	return nil
+}
+
+func (self *Exporter) collectNetProtoV4(m *metrics) error {
+	stats, err := self.sigar.GetNetProtoV4Stats()
+	if err != nil {
+		return err
+	}
+
+	addNetstat(m, "Ip", stats.IP, stats.ICMP, stats.TCP, stats.UDP)
+	return nil
+}
+
+func (self *Exporter) collectNetProtoV6(m *metrics) error {
+	stats, err := self.sigar.GetNetProtoV6Stats()
+	if err != nil {
+		return err
+	}
+
+	addNetstat(m, "Ip6", stats.IP, stats.ICMP, stats.TCP, stats.UDP)
+	return nil
+}
+
+// Add /proc/net/snmp style counters, e.g. node_netstat_Tcp_ActiveOpens.
+// ipProto is "Ip" or "Ip6", which also selects the suffix of the other protocols.
+func addNetstat(m *metrics, ipProto string, ip sigar.IPStats, icmp sigar.ICMPStats, tcp sigar.TCPStats, udp sigar.UDPStats) {
+	suffix := ""
+	if ipProto == "Ip6" {
+		suffix = "6"
+	}
+
+	add := func(proto, field string, value uint64) {
+		name := "node_netstat_" + proto + "_" + field
+		m.add(name, counter, "Statistic "+proto+field+".", float64(value))
+	}
+
+	add(ipProto, "InReceives", ip.InReceives)
+	add(ipProto, "InHdrErrors", ip.InHdrErrors)
+	add(ipProto, "InAddrErrors", ip.InAddrErrors)
+	add(ipProto, "ForwDatagrams", ip.ForwDatagrams)
+	add(ipProto, "InDelivers", ip.InDelivers)
+	add(ipProto, "InDiscards", ip.InDiscards)
+	add(ipProto, "InUnknownProtos", ip.InUnknownProtos)
+	add(ipProto, "OutRequests", ip.OutRequests)
+	add(ipProto, "OutDiscards", ip.OutDiscards)
+	add(ipProto, "OutNoRoutes", ip.OutNoRoutes)
+
+	add("Icmp"+suffix, "InMsgs", icmp.InMsgs)
+	add("Icmp"+suffix, "InErrors", icmp.InErrors)
+	add("Icmp"+suffix, "InDestUnreachs", icmp.InDestUnreachs)
+	add("Icmp"+suffix, "OutMsgs", icmp.OutMsgs)
+	add("Icmp"+suffix, "OutErrors", icmp.OutErrors)
+	add("Icmp"+suffix, "OutDestUnreachs", icmp.OutDestUnreachs)
+
+	// snmp6 has no TCP section
+	if suffix == "" {
+		add("Tcp", "ActiveOpens", tcp.ActiveOpens)
+		add("Tcp", "PassiveOpens", tcp.PassiveOpens)
+		add("Tcp", "AttemptFails", tcp.AttemptFails)
+		add("Tcp", "EstabResets", tcp.EstabResets)
+		m.add("node_netstat_Tcp_CurrEstab", gauge, "Statistic TcpCurrEstab.", float64(tcp.CurrEstab))
+		add("Tcp", "InSegs", tcp.InSegs)
+		add("Tcp", "OutSegs", tcp.OutSegs)
+		add("Tcp", "RetransSegs", tcp.RetransSegs)
+		add("Tcp", "InErrs", tcp.InErrs)
+		add("Tcp", "OutRsts", tcp.OutRsts)
+	}
+
+	add("Udp"+suffix, "InDatagrams", udp.InDatagrams)
+	add("Udp"+suffix, "OutDatagrams", udp.OutDatagrams)
+	add("Udp"+suffix, "InErrors", udp.InErrors)
+	add("Udp"+suffix, "NoPorts", udp.NoPorts)
+	add("Udp"+suffix, "RcvbufErrors", udp.RcvbufErrors)
+	add("Udp"+suffix, "SndbufErrors", udp.SndbufErrors)
+}
+
+func (self *Exporter) collectConnections(m *metrics) error {
+	connList, err := self.sigar.GetNetConnList()
+	if err != nil {
+		return err
+	}
+	lists := []struct {
+		family string
+		conns  []sigar.NetConn
+	}{
+		{"ipv4", connList.V4},
+		{"ipv6", connList.V6},
+	}
+
+	type key struct {
+		proto, family, state string
+	}
+	counts := make(map[key]int)
+	var keys []key
+
+	for _, list := range lists {
+		for _, conn := range list.conns {
+			state := conn.Status.String()
+			if state == "" {
+				state = "none"
+			}
+			k := key{conn.Proto.String(), list.family, state}
+			if _, ok := counts[k]; !ok {
+				keys = append(keys, k)
+			}
+			counts[k]++
+		}
+	}
+
+	for _, k := range keys {
+		m.add("sigar_network_connections", gauge, "Number of open sockets by protocol, address family and state.",
+			float64(counts[k]), "protocol", k.proto, "family", k.family, "state", k.state)
+	}
+	return nil
+}
+
+func (self *Exporter) collectProcesses(m *metrics) error {
+	processes, err := self.sigar.GetProcessList()
+	if err != nil && !sigar.IsPartial(err) {
+		return err
+	}
+
+	states := make(map[string]int)
+	var names []string
+	for _, process := range processes.List {
+		state := string(process.ProcState.State)
+		if _, ok := states[state]; !ok {
+			names = append(names, state)
+		}
+		states[state]++
+	}
+	sort.Strings(names)
+	for _, state := range names {
+		m.add("node_processes_state", gauge, "Number of processes in each state.", float64(states[state]), "state", state)
+	}
+
+	fds := make(map[int]sigar.ProcFd)
+	if self.options.ProcessFds {
+		fdUsage, fdErr := self.sigar.GetProcFdUsageList()
+		if fdErr != nil && !sigar.IsPartial(fdErr) {
+			return fdErr
+		}
+		if err == nil {
+			err = fdErr
+		}
+
+		nearFdLimit := 0
+		for _, usage := range fdUsage.List {
+			fds[usage.Pid] = usage.ProcFd
+			if usage.UsePercent() > self.options.FdLimitFraction*100 {
+				nearFdLimit++
+			}
+		}
+		m.add("sigar_processes_near_fd_limit", gauge, "Number of processes past the configured fraction of their open files limit.",
+			float64(nearFdLimit))
+	}
+
+	if !self.options.Processes {
+		return err
+	}
+
+	for _, process := range processes.List {
+		pid := strconv.Itoa(process.Pid)
+		labels := []string{"pid", pid, "name", process.ProcState.Name}
+
+		m.add("sigar_process_cpu_seconds_total", counter, "CPU time spent by the process in each mode.",
+			float64(process.ProcTime.User)/1000, append(labels, "mode", "user")...)
+		m.add("sigar_process_cpu_seconds_total", counter, "CPU time spent by the process in each mode.",
+			float64(process.ProcTime.Sys)/1000, append(labels, "mode", "system")...)
+		m.add("sigar_process_start_time_seconds", gauge, "Start time of the process since unix epoch in seconds.",
+			float64(process.ProcTime.StartTime)/1000, labels...)
+		m.add("sigar_process_virtual_memory_bytes", gauge, "Virtual memory size in bytes.",
+			float64(process.ProcMem.Size), labels...)
+		m.add("sigar_process_resident_memory_bytes", gauge, "Resident memory size in bytes.",
+			float64(process.ProcMem.Resident), labels...)
+		m.add("sigar_process_major_page_faults_total", counter, "Major page faults of the process.",
+			float64(process.ProcMem.MajorFaults), labels...)
+		m.add("sigar_process_minor_page_faults_total", counter, "Minor page faults of the process.",
+			float64(process.ProcMem.MinorFaults), labels...)
+		m.add("sigar_process_read_bytes_total", counter, "Bytes read from storage by the process.",
+			float64(process.ProcIo.ReadBytes), labels...)
+		m.add("sigar_process_written_bytes_total", counter, "Bytes written to storage by the process.",
+			float64(process.ProcIo.WriteBytes), labels...)
+
+		// Processes whose fds couldn't be read are missing from fds
+		if fd, ok := fds[process.Pid]; ok {
+			m.add("sigar_process_open_fds", gauge, "Number of open file descriptors.",
+				float64(fd.Open), labels...)
+			if fd.SoftLimit != sigar.RlimInfinity {
+				m.add("sigar_process_max_fds", gauge, "Soft limit on the number of open file descriptors.",
+					float64(fd.SoftLimit), labels...)
+			}
+		}
+	}
+	return err
+}
+
+func (self *Exporter) collectSystemFd(m *metrics) error {
+	fd, err := self.sigar.GetSystemFd()
+	if err != nil {
+		return err
+	}
+
+	m.add("node_filefd_allocated", gauge, "File descriptor statistics: allocated.", float64(fd.Allocated))
+	m.add("node_filefd_maximum", gauge, "File descriptor statistics: maximum.", float64(fd.Max))
+	return nil
+}
+
+func (self *Exporter) collectSystemInfo(m *metrics) error {
+	info, err := self.sigar.GetSystemInfo()
+	if err != nil {
+		return err
+	}
+
+	m.add("node_uname_info", gauge, "Labeled system information as provided by the uname system call.", 1,
+		"sysname", info.Sysname,
+		"release", info.Release,
+		"version", info.Version,
+		"machine", info.Machine,
+		"nodename", info.Nodename,
+		"domainname", info.Domainname)
+	return nil
+}
+
+func (self *Exporter) collectSystemDistribution(m *metrics) error {
+	dist, err := self.sigar.GetSystemDistribution()
+	if err != nil {
+		return err
+	}
+
+	m.add("node_os_info", gauge, "A metric with a constant '1' value labeled by the operating system description.", 1,
+		"pretty_name", dist.Description)
+	return nil
+}
@@ -0,0 +1,13 @@
+package prometheus_test
+
+import (
+	. "github.com/scalingdata/ginkgo"
+	. "github.com/scalingdata/gomega"
+
+	"testing"
+)
+
+func TestPrometheus(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Prometheus Suite")
+}
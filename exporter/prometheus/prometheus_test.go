@@ -0,0 +1,308 @@
+package prometheus_test
+
+import (
+	"bytes"
+	"errors"
+	"log"
+	"math"
+	"net/http/httptest"
+
+	. "github.com/scalingdata/ginkgo"
+	. "github.com/scalingdata/gomega"
+
+	sigar "github.com/scalingdata/gosigar"
+	"github.com/scalingdata/gosigar/exporter/prometheus"
+	"github.com/scalingdata/gosigar/fakes"
+)
+
+var _ = Describe("Exporter", func() {
+	var (
+		fakeSigar *fakes.FakeSigar
+		exporter  *prometheus.Exporter
+	)
+
+	BeforeEach(func() {
+		fakeSigar = fakes.NewFakeSigar()
+		exporter = prometheus.New(fakeSigar, prometheus.Options{})
+	})
+
+	write := func() string {
+		var buf bytes.Buffer
+		Expect(exporter.Write(&buf)).To(Succeed())
+		return buf.String()
+	}
+
+	It("writes cpu times in seconds per cpu and mode", func() {
+		fakeSigar.CpuList = sigar.CpuList{List: []sigar.Cpu{{User: 250, Idle: 1000}, {Sys: 50}}}
+
+		out := write()
+		Expect(out).To(ContainSubstring("# TYPE node_cpu_seconds_total counter\n"))
+		Expect(out).To(ContainSubstring(`node_cpu_seconds_total{cpu="0",mode="user"} 2.5` + "\n"))
+		Expect(out).To(ContainSubstring(`node_cpu_seconds_total{cpu="0",mode="idle"} 10` + "\n"))
+		Expect(out).To(ContainSubstring(`node_cpu_seconds_total{cpu="1",mode="system"} 0.5` + "\n"))
+	})
+
+	It("writes cpu info and frequencies, and marks offline cpus", func() {
+		fakeSigar.CpuInfo = sigar.CpuInfo{List: []sigar.LogicalCpu{
+			{Id: 0, Online: true, Vendor: "GenuineIntel", ModelName: "Xeon", CoreId: 1, CurFreq: 2100000000, Governor: "performance"},
+			{Id: 1, SocketId: -1, CoreId: -1, ThreadId: -1},
+		}}
+
+		out := write()
+		Expect(out).To(ContainSubstring(`node_cpu_info{cpu="0",vendor="GenuineIntel",model_name="Xeon",package="0",core="1"} 1` + "\n"))
+		Expect(out).To(ContainSubstring(`node_cpu_scaling_frequency_hertz{cpu="0"} 2.1e+09` + "\n"))
+		Expect(out).To(ContainSubstring(`node_cpu_scaling_governor{cpu="0",governor="performance"} 1` + "\n"))
+		Expect(out).To(ContainSubstring(`node_cpu_online{cpu="1"} 0` + "\n"))
+		Expect(out).ToNot(ContainSubstring(`node_cpu_info{cpu="1"`))
+	})
+
+	It("writes every meminfo field", func() {
+		fakeSigar.MemInfo = sigar.MemInfo{
+			MemTotal:       2048,
+			ActiveAnon:     512,
+			CommittedAS:    4096,
+			HugePagesTotal: 4,
+			Other:          map[string]uint64{"Zswap": 64},
+		}
+
+		out := write()
+		Expect(out).To(ContainSubstring("node_memory_MemTotal_bytes 2048\n"))
+		Expect(out).To(ContainSubstring("node_memory_Active_anon_bytes 512\n"))
+		Expect(out).To(ContainSubstring("node_memory_Committed_AS_bytes 4096\n"))
+		Expect(out).To(ContainSubstring("node_memory_HugePages_Total 4\n"))
+		Expect(out).To(ContainSubstring("node_memory_Zswap_bytes 64\n"))
+		Expect(out).NotTo(ContainSubstring("node_memory_MemAvailable_bytes"))
+
+		fakeSigar.MemInfo.HasMemAvailable = true
+		Expect(write()).To(ContainSubstring("node_memory_MemAvailable_bytes 0\n"))
+	})
+
+	It("writes vmstat counters and gauges", func() {
+		fakeSigar.VmStat = sigar.VmStat{OomKill: 3, Other: map[string]uint64{"nr_dirty": 12}}
+
+		out := write()
+		Expect(out).To(ContainSubstring("# TYPE node_vmstat_oom_kill counter\nnode_vmstat_oom_kill 3\n"))
+		Expect(out).To(ContainSubstring("# TYPE node_vmstat_nr_dirty gauge\nnode_vmstat_nr_dirty 12\n"))
+	})
+
+	It("writes memory and swap in bytes where meminfo is not implemented", func() {
+		fakeSigar.MemInfoErr = sigar.ErrNotImplemented
+		fakeSigar.Mem = sigar.Mem{Total: 2048, Free: 1024, ActualFree: 1536}
+		fakeSigar.Swap = sigar.Swap{Total: 4096, Free: 4096}
+
+		out := write()
+		Expect(out).To(ContainSubstring("# TYPE node_memory_MemTotal_bytes gauge\n"))
+		Expect(out).To(ContainSubstring("node_memory_MemTotal_bytes 2048\n"))
+		Expect(out).To(ContainSubstring("node_memory_MemAvailable_bytes 1536\n"))
+		Expect(out).To(ContainSubstring("node_memory_SwapFree_bytes 4096\n"))
+	})
+
+	It("writes numa node memory, counters and topology", func() {
+		fakeSigar.NumaNodeList = sigar.NumaNodeList{List: []sigar.NumaNode{
+			{Id: 0, Cpus: []int{0, 1}, Distances: []int{10, 21}, MemTotal: 4096, NumaStat: sigar.NumaStat{NumaHit: 7}},
+			{Id: 1, Cpus: []int{2}, Distances: []int{21, 10}},
+		}}
+
+		out := write()
+		Expect(out).To(ContainSubstring(`node_memory_numa_MemTotal{node="0"} 4096` + "\n"))
+		Expect(out).To(ContainSubstring("# TYPE node_memory_numa_numa_hit_total counter\n"))
+		Expect(out).To(ContainSubstring(`node_memory_numa_numa_hit_total{node="0"} 7` + "\n"))
+		Expect(out).To(ContainSubstring(`sigar_numa_node_cpu_info{node="1",cpu="2"} 1` + "\n"))
+		Expect(out).To(ContainSubstring(`sigar_numa_node_distance{node="0",to="1"} 21` + "\n"))
+	})
+
+	It("writes filesystem usage in bytes and skips pseudo filesystems", func() {
+		fakeSigar.FileSystemList = sigar.FileSystemList{List: []sigar.FileSystem{
+			{DirName: "/", DevName: "/dev/sda1", SysTypeName: "ext4"},
+			{DirName: "/proc", DevName: "proc", SysTypeName: "proc"},
+		}}
+		fakeSigar.FileSystemUsage = sigar.FileSystemUsage{Total: 10240, Free: 4096, Avail: 3072, Files: 100, FreeFiles: 50, ReadOnly: true}
+
+		out := write()
+		Expect(out).To(ContainSubstring(`node_filesystem_size_bytes{device="/dev/sda1",fstype="ext4",mountpoint="/"} 10240` + "\n"))
+		Expect(out).To(ContainSubstring(`node_filesystem_avail_bytes{device="/dev/sda1",fstype="ext4",mountpoint="/"} 3072` + "\n"))
+		Expect(out).To(ContainSubstring(`node_filesystem_readonly{device="/dev/sda1",fstype="ext4",mountpoint="/"} 1` + "\n"))
+		Expect(out).To(ContainSubstring(`node_filesystem_files_free{device="/dev/sda1",fstype="ext4",mountpoint="/"} 50` + "\n"))
+		Expect(out).NotTo(ContainSubstring(`mountpoint="/proc"`))
+		Expect(fakeSigar.FileSystemUsagePath).To(Equal("/"))
+	})
+
+	It("writes disk counters per device, sorted by name", func() {
+		fakeSigar.DiskList = sigar.DiskList{List: map[string]sigar.DiskIo{
+			"sdb": {ReadOps: 2},
+			"sda": {ReadOps: 1, WriteBytes: 512, IoTimeMs: 1500, IoInProgress: 4, DiscardBytes: 1024},
+		}}
+
+		out := write()
+		Expect(out).To(ContainSubstring(`node_disk_reads_completed_total{device="sda"} 1` + "\n" +
+			`node_disk_reads_completed_total{device="sdb"} 2` + "\n"))
+		Expect(out).To(ContainSubstring(`node_disk_written_bytes_total{device="sda"} 512` + "\n"))
+		Expect(out).To(ContainSubstring(`node_disk_io_time_seconds_total{device="sda"} 1.5` + "\n"))
+		Expect(out).To(ContainSubstring("# TYPE node_disk_io_now gauge\n" + `node_disk_io_now{device="sda"} 4` + "\n"))
+		Expect(out).To(ContainSubstring(`node_disk_discarded_sectors_total{device="sda"} 2` + "\n"))
+	})
+
+	It("writes block device information", func() {
+		fakeSigar.BlockDeviceList = sigar.BlockDeviceList{List: []sigar.BlockDevice{
+			{Name: "dm-0", Size: 4096, MapperName: "vg-root"},
+			{Name: "sda", Size: 8192, Model: "QEMU", Rotational: true, Scheduler: "bfq"},
+		}}
+
+		out := write()
+		Expect(out).To(ContainSubstring(`sigar_block_device_info{device="dm-0",parent="",model="",serial="",rotational="0",scheduler="",mapper_name="vg-root"} 1` + "\n"))
+		Expect(out).To(ContainSubstring(`sigar_block_device_info{device="sda",parent="",model="QEMU",serial="",rotational="1",scheduler="bfq",mapper_name=""} 1` + "\n"))
+		Expect(out).To(ContainSubstring(`sigar_block_device_size_bytes{device="sda"} 8192` + "\n"))
+	})
+
+	It("writes md array health and sync progress", func() {
+		fakeSigar.MdArrayList = sigar.MdArrayList{List: []sigar.MdArray{{
+			Name:          "md1",
+			Active:        true,
+			State:         "clean",
+			Size:          2048 * 1024,
+			RaidDisks:     3,
+			ActiveDisks:   2,
+			Degraded:      1,
+			Failed:        1,
+			SyncAction:    "recover",
+			SyncCompleted: 0.25,
+		}}}
+
+		out := write()
+		Expect(out).To(ContainSubstring(`node_md_state{device="md1",state="clean"} 1` + "\n"))
+		Expect(out).To(ContainSubstring(`node_md_disks{device="md1",state="failed"} 1` + "\n"))
+		Expect(out).To(ContainSubstring(`sigar_md_degraded{device="md1"} 1` + "\n"))
+		Expect(out).To(ContainSubstring(`node_md_blocks_synced{device="md1"} 512` + "\n"))
+	})
+
+	It("writes network interface and protocol counters", func() {
+		fakeSigar.NetIfaceList = sigar.NetIfaceList{List: []sigar.NetIface{
+			{Name: "eth0", RecvBytes: 100, SendPackets: 7, MTU: 1500, LinkStatus: "UP"},
+		}}
+		fakeSigar.NetProtoV4Stats.TCP.CurrEstab = 3
+		fakeSigar.NetProtoV4Stats.TCP.ActiveOpens = 9
+
+		out := write()
+		Expect(out).To(ContainSubstring(`node_network_receive_bytes_total{device="eth0"} 100` + "\n"))
+		Expect(out).To(ContainSubstring(`node_network_transmit_packets_total{device="eth0"} 7` + "\n"))
+		Expect(out).To(ContainSubstring(`node_network_mtu_bytes{device="eth0"} 1500` + "\n"))
+		Expect(out).To(ContainSubstring(`node_network_up{device="eth0"} 1` + "\n"))
+		Expect(out).To(ContainSubstring("# TYPE node_netstat_Tcp_CurrEstab gauge\nnode_netstat_Tcp_CurrEstab 3\n"))
+		Expect(out).To(ContainSubstring("# TYPE node_netstat_Tcp_ActiveOpens counter\nnode_netstat_Tcp_ActiveOpens 9\n"))
+	})
+
+	It("counts processes by state and only exports per-process metrics when asked", func() {
+		process := sigar.Process{}
+		process.ProcState = sigar.ProcState{Pid: 42, Name: "redis", State: sigar.RunStateSleep}
+		process.ProcMem.Resident = 4096
+		fakeSigar.ProcessList = sigar.ProcessList{List: []sigar.Process{process}}
+
+		out := write()
+		Expect(out).To(ContainSubstring(`node_processes_state{state="S"} 1` + "\n"))
+		Expect(out).NotTo(ContainSubstring("sigar_process_"))
+
+		exporter = prometheus.New(fakeSigar, prometheus.Options{Processes: true})
+		Expect(write()).To(ContainSubstring(`sigar_process_resident_memory_bytes{pid="42",name="redis"} 4096` + "\n"))
+	})
+
+	It("counts processes near their open files limit", func() {
+		busy := sigar.Process{}
+		busy.ProcState = sigar.ProcState{Pid: 42, Name: "redis"}
+		idle := sigar.Process{}
+		idle.ProcState = sigar.ProcState{Pid: 43, Name: "cron"}
+		fakeSigar.ProcessList = sigar.ProcessList{List: []sigar.Process{busy, idle}}
+		fakeSigar.ProcFdUsageList = sigar.ProcFdUsageList{List: []sigar.ProcFdUsage{
+			{Pid: 42, ProcFd: sigar.ProcFd{Open: 900, SoftLimit: 1024, HardLimit: 4096}},
+			{Pid: 43, ProcFd: sigar.ProcFd{Open: 5, SoftLimit: sigar.RlimInfinity, HardLimit: sigar.RlimInfinity}},
+		}}
+		fakeSigar.SystemFd = sigar.SystemFd{Allocated: 1200, Max: 9223372036854775807}
+
+		out := write()
+		Expect(out).To(ContainSubstring("node_filefd_allocated 1200\n"))
+		Expect(out).NotTo(ContainSubstring("sigar_processes_near_fd_limit"))
+
+		exporter = prometheus.New(fakeSigar, prometheus.Options{ProcessFds: true})
+		out = write()
+		Expect(out).To(ContainSubstring("sigar_processes_near_fd_limit 1\n"))
+		Expect(out).NotTo(ContainSubstring("sigar_process_open_fds"))
+
+		exporter = prometheus.New(fakeSigar, prometheus.Options{Processes: true, ProcessFds: true, FdLimitFraction: 0.9})
+		out = write()
+		Expect(out).To(ContainSubstring("sigar_processes_near_fd_limit 0\n"))
+		Expect(out).To(ContainSubstring(`sigar_process_open_fds{pid="43",name="cron"} 5` + "\n"))
+		Expect(out).To(ContainSubstring(`sigar_process_max_fds{pid="42",name="redis"} 1024` + "\n"))
+		Expect(out).NotTo(ContainSubstring(`sigar_process_max_fds{pid="43"`))
+	})
+
+	It("counts connections by protocol, family and state", func() {
+		fakeSigar.NetConnList = sigar.NetConnList{
+			V4: []sigar.NetConn{
+				{Proto: sigar.ConnProtoTcp, Status: sigar.ConnStateListen},
+				{Proto: sigar.ConnProtoTcp, Status: sigar.ConnStateListen},
+				{Proto: sigar.ConnProtoUdp},
+			},
+			V6: []sigar.NetConn{{Proto: sigar.ConnProtoTcp, Status: sigar.ConnStateListen}},
+		}
+
+		out := write()
+		Expect(out).To(ContainSubstring(`sigar_network_connections{protocol="tcp",family="ipv4",state="listen"} 2` + "\n"))
+		Expect(out).To(ContainSubstring(`sigar_network_connections{protocol="udp",family="ipv4",state="none"} 1` + "\n"))
+		Expect(out).To(ContainSubstring(`sigar_network_connections{protocol="tcp",family="ipv6",state="listen"} 1` + "\n"))
+	})
+
+	It("escapes label values", func() {
+		fakeSigar.SystemDistribution = sigar.SystemDistribution{Description: "A \"quoted\"\\name"}
+
+		Expect(write()).To(ContainSubstring(`node_os_info{pretty_name="A \"quoted\"\\name"} 1` + "\n"))
+	})
+
+	It("reports failed collectors and skips unimplemented ones", func() {
+		fakeSigar.MemInfoErr = errors.New("boom")
+		fakeSigar.PressureErr = sigar.ErrNotImplemented
+		fakeSigar.LoadAverage = sigar.LoadAverage{One: math.Inf(1)}
+
+		out := write()
+		Expect(out).To(ContainSubstring(`sigar_scrape_collector_success{collector="meminfo"} 0` + "\n"))
+		Expect(out).To(ContainSubstring(`sigar_scrape_collector_success{collector="cpu"} 1` + "\n"))
+		Expect(out).NotTo(ContainSubstring(`collector="pressure"`))
+		Expect(out).NotTo(ContainSubstring("node_memory_MemTotal_bytes"))
+		Expect(out).To(ContainSubstring("node_load1 +Inf\n"))
+	})
+
+	It("serves metrics over HTTP", func() {
+		fakeSigar.LoadAverage = sigar.LoadAverage{One: 1}
+
+		recorder := httptest.NewRecorder()
+		exporter.ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+
+		Expect(recorder.Code).To(Equal(200))
+		Expect(recorder.Header().Get("Content-Type")).To(Equal(prometheus.ContentType))
+		Expect(recorder.Body.String()).To(ContainSubstring("node_load1 1\n"))
+	})
+
+	It("logs the errors of failed collectors", func() {
+		var logged bytes.Buffer
+		exporter = prometheus.New(fakeSigar, prometheus.Options{ErrorLog: log.New(&logged, "", 0)})
+		fakeSigar.LoadAverageErr = errors.New("boom")
+
+		Expect(write()).To(ContainSubstring(`sigar_scrape_collector_success{collector="loadavg"} 0` + "\n"))
+		Expect(logged.String()).To(Equal("Error collecting loadavg: boom\n"))
+	})
+
+	It("logs metrics it fails to send", func() {
+		var logged bytes.Buffer
+		exporter = prometheus.New(fakeSigar, prometheus.Options{ErrorLog: log.New(&logged, "", 0)})
+
+		exporter.ServeHTTP(&hungUpWriter{httptest.NewRecorder()}, httptest.NewRequest("GET", "/metrics", nil))
+		Expect(logged.String()).To(Equal("Error sending metrics: connection reset by peer\n"))
+	})
+})
+
+// A response whose client went away
+type hungUpWriter struct {
+	*httptest.ResponseRecorder
+}
+
+func (self *hungUpWriter) Write([]byte) (int, error) {
+	return 0, errors.New("connection reset by peer")
+}
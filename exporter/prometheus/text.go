@@ -0,0 +1,97 @@
+package prometheus
+
+import (
+	"bufio"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+const (
+	counter = "counter"
+	gauge   = "gauge"
+)
+
+// ContentType of the Prometheus text exposition format written by Exporter
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+type sample struct {
+	labels []string // Alternating label names and values
+	value  float64
+}
+
+type family struct {
+	name    string
+	help    string
+	typ     string
+	samples []sample
+}
+
+// metrics collects samples grouped by family, since the text format requires
+// every sample of a family to be written together.
+type metrics struct {
+	families []*family
+	byName   map[string]*family
+}
+
+func newMetrics() *metrics {
+	return &metrics{byName: make(map[string]*family)}
+}
+
+// Add a sample. labels alternate between names and values.
+func (self *metrics) add(name, typ, help string, value float64, labels ...string) {
+	f, ok := self.byName[name]
+	if !ok {
+		f = &family{name: name, help: help, typ: typ}
+		self.byName[name] = f
+		self.families = append(self.families, f)
+	}
+	f.samples = append(f.samples, sample{labels: labels, value: value})
+}
+
+func (self *metrics) write(out io.Writer) error {
+	w := bufio.NewWriter(out)
+	for _, f := range self.families {
+		w.WriteString("# HELP " + f.name + " " + escapeHelp(f.help) + "\n")
+		w.WriteString("# TYPE " + f.name + " " + f.typ + "\n")
+		for _, s := range f.samples {
+			w.WriteString(f.name)
+			if len(s.labels) > 0 {
+				w.WriteByte('{')
+				for i := 0; i+1 < len(s.labels); i += 2 {
+					if i > 0 {
+						w.WriteByte(',')
+					}
+					w.WriteString(s.labels[i] + `="` + escapeLabelValue(s.labels[i+1]) + `"`)
+				}
+				w.WriteByte('}')
+			}
+			w.WriteString(" " + formatValue(s.value) + "\n")
+		}
+	}
+	return w.Flush()
+}
+
+var helpEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+var labelValueEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+
+func escapeHelp(help string) string {
+	return helpEscaper.Replace(help)
+}
+
+func escapeLabelValue(value string) string {
+	return labelValueEscaper.Replace(value)
+}
+
+func formatValue(value float64) string {
+	switch {
+	case math.IsNaN(value):
+		return "NaN"
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	}
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
@@ -7,27 +7,175 @@ import (
 )
 
 type FakeSigar struct {
+	Cpu    sigar.Cpu
+	CpuErr error
+
+	CpuList    sigar.CpuList
+	CpuListErr error
+
 	LoadAverage    sigar.LoadAverage
 	LoadAverageErr error
 
+	Uptime    sigar.Uptime
+	UptimeErr error
+
 	Mem    sigar.Mem
 	MemErr error
 
 	Swap    sigar.Swap
 	SwapErr error
 
+	FileSystemList    sigar.FileSystemList
+	FileSystemListErr error
+
+	ProcFileSystemList    sigar.FileSystemList
+	ProcFileSystemListErr error
+	ProcFileSystemListPid int
+
 	FileSystemUsage     sigar.FileSystemUsage
 	FileSystemUsageErr  error
 	FileSystemUsagePath string
 
+	DirUsage        sigar.DirUsage
+	DirUsageErr     error
+	DirUsagePath    string
+	DirUsageOptions sigar.DirUsageOptions
+
+	DiskList    sigar.DiskList
+	DiskListErr error
+
+	NetProtoV4Stats    sigar.NetProtoV4Stats
+	NetProtoV4StatsErr error
+
+	NetProtoV6Stats    sigar.NetProtoV6Stats
+	NetProtoV6StatsErr error
+
+	NetIfaceList    sigar.NetIfaceList
+	NetIfaceListErr error
+
+	NetTcpConnList    sigar.NetTcpConnList
+	NetTcpConnListErr error
+
+	NetUdpConnList    sigar.NetUdpConnList
+	NetUdpConnListErr error
+
+	NetRawConnList    sigar.NetRawConnList
+	NetRawConnListErr error
+
+	NetTcpV6ConnList    sigar.NetTcpV6ConnList
+	NetTcpV6ConnListErr error
+
+	NetUdpV6ConnList    sigar.NetUdpV6ConnList
+	NetUdpV6ConnListErr error
+
+	NetRawV6ConnList    sigar.NetRawV6ConnList
+	NetRawV6ConnListErr error
+
+	NetConnList    sigar.NetConnList
+	NetConnListErr error
+
+	ProcessList    sigar.ProcessList
+	ProcessListErr error
+
+	ProcList    sigar.ProcList
+	ProcListErr error
+
+	ProcState    sigar.ProcState
+	ProcStateErr error
+	ProcStatePid int
+
+	ProcStatus    sigar.ProcStatus
+	ProcStatusErr error
+	ProcStatusPid int
+
+	ProcIo    sigar.ProcIo
+	ProcIoErr error
+	ProcIoPid int
+
+	ProcMem    sigar.ProcMem
+	ProcMemErr error
+	ProcMemPid int
+
+	ProcTime    sigar.ProcTime
+	ProcTimeErr error
+	ProcTimePid int
+
+	ProcArgs    sigar.ProcArgs
+	ProcArgsErr error
+	ProcArgsPid int
+
+	ProcExe    sigar.ProcExe
+	ProcExeErr error
+	ProcExePid int
+
+	ProcFd    sigar.ProcFd
+	ProcFdErr error
+	ProcFdPid int
+
+	ProcLimits    sigar.ProcLimits
+	ProcLimitsErr error
+	ProcLimitsPid int
+
+	ProcFdUsageList    sigar.ProcFdUsageList
+	ProcFdUsageListErr error
+
+	ProcFdList    sigar.ProcFdList
+	ProcFdListErr error
+	ProcFdListPid int
+
+	SystemInfo    sigar.SystemInfo
+	SystemInfoErr error
+
+	SystemDistribution    sigar.SystemDistribution
+	SystemDistributionErr error
+
+	SystemFd    sigar.SystemFd
+	SystemFdErr error
+
+	DeletedOpenFiles    sigar.DeletedOpenFiles
+	DeletedOpenFilesErr error
+
+	MdArrayList    sigar.MdArrayList
+	MdArrayListErr error
+
+	BlockDeviceList    sigar.BlockDeviceList
+	BlockDeviceListErr error
+
+	CpuInfo    sigar.CpuInfo
+	CpuInfoErr error
+
+	NumaNodeList    sigar.NumaNodeList
+	NumaNodeListErr error
+
+	SwapList    sigar.SwapList
+	SwapListErr error
+
+	VmStat    sigar.VmStat
+	VmStatErr error
+
+	MemInfo    sigar.MemInfo
+	MemInfoErr error
+
+	Pressure    sigar.Pressure
+	PressureErr error
+
+	KernelStat    sigar.KernelStat
+	KernelStatErr error
+
 	CollectCpuStatsCpuCh  chan sigar.Cpu
 	CollectCpuStatsStopCh chan struct{}
+
+	CollectCpuListPercentCh     chan sigar.CpuListPercent
+	CollectCpuListPercentStopCh chan struct{}
 }
 
 func NewFakeSigar() *FakeSigar {
 	return &FakeSigar{
 		CollectCpuStatsCpuCh:  make(chan sigar.Cpu, 1),
 		CollectCpuStatsStopCh: make(chan struct{}),
+
+		CollectCpuListPercentCh:     make(chan sigar.CpuListPercent, 1),
+		CollectCpuListPercentStopCh: make(chan struct{}),
 	}
 }
 
@@ -54,10 +202,45 @@ func (f *FakeSigar) CollectCpuStats(collectionInterval time.Duration) (<-chan si
 	return samplesCh, stopCh
 }
 
+func (f *FakeSigar) CollectCpuListPercent(collectionInterval time.Duration) (<-chan sigar.CpuListPercent, chan<- struct{}) {
+	samplesCh := make(chan sigar.CpuListPercent, 1)
+	stopCh := make(chan struct{})
+
+	go func() {
+		for {
+			select {
+			case percent := <-f.CollectCpuListPercentCh:
+				select {
+				case samplesCh <- percent:
+				default:
+					// Include default to avoid channel blocking
+				}
+
+			case <-f.CollectCpuListPercentStopCh:
+				return
+			}
+		}
+	}()
+
+	return samplesCh, stopCh
+}
+
+func (f *FakeSigar) GetCpu() (sigar.Cpu, error) {
+	return f.Cpu, f.CpuErr
+}
+
+func (f *FakeSigar) GetCpuList() (sigar.CpuList, error) {
+	return f.CpuList, f.CpuListErr
+}
+
 func (f *FakeSigar) GetLoadAverage() (sigar.LoadAverage, error) {
 	return f.LoadAverage, f.LoadAverageErr
 }
 
+func (f *FakeSigar) GetUptime() (sigar.Uptime, error) {
+	return f.Uptime, f.UptimeErr
+}
+
 func (f *FakeSigar) GetMem() (sigar.Mem, error) {
 	return f.Mem, f.MemErr
 }
@@ -66,7 +249,180 @@ func (f *FakeSigar) GetSwap() (sigar.Swap, error) {
 	return f.Swap, f.SwapErr
 }
 
+func (f *FakeSigar) GetFileSystemList() (sigar.FileSystemList, error) {
+	return f.FileSystemList, f.FileSystemListErr
+}
+
+func (f *FakeSigar) GetProcFileSystemList(pid int) (sigar.FileSystemList, error) {
+	f.ProcFileSystemListPid = pid
+	return f.ProcFileSystemList, f.ProcFileSystemListErr
+}
+
 func (f *FakeSigar) GetFileSystemUsage(path string) (sigar.FileSystemUsage, error) {
 	f.FileSystemUsagePath = path
 	return f.FileSystemUsage, f.FileSystemUsageErr
 }
+
+func (f *FakeSigar) GetDirUsage(path string, options sigar.DirUsageOptions) (sigar.DirUsage, error) {
+	f.DirUsagePath = path
+	f.DirUsageOptions = options
+	return f.DirUsage, f.DirUsageErr
+}
+
+func (f *FakeSigar) GetDiskList() (sigar.DiskList, error) {
+	return f.DiskList, f.DiskListErr
+}
+
+func (f *FakeSigar) GetNetProtoV4Stats() (sigar.NetProtoV4Stats, error) {
+	return f.NetProtoV4Stats, f.NetProtoV4StatsErr
+}
+
+func (f *FakeSigar) GetNetProtoV6Stats() (sigar.NetProtoV6Stats, error) {
+	return f.NetProtoV6Stats, f.NetProtoV6StatsErr
+}
+
+func (f *FakeSigar) GetNetIfaceList() (sigar.NetIfaceList, error) {
+	return f.NetIfaceList, f.NetIfaceListErr
+}
+
+func (f *FakeSigar) GetNetTcpConnList() (sigar.NetTcpConnList, error) {
+	return f.NetTcpConnList, f.NetTcpConnListErr
+}
+
+func (f *FakeSigar) GetNetUdpConnList() (sigar.NetUdpConnList, error) {
+	return f.NetUdpConnList, f.NetUdpConnListErr
+}
+
+func (f *FakeSigar) GetNetRawConnList() (sigar.NetRawConnList, error) {
+	return f.NetRawConnList, f.NetRawConnListErr
+}
+
+func (f *FakeSigar) GetNetTcpV6ConnList() (sigar.NetTcpV6ConnList, error) {
+	return f.NetTcpV6ConnList, f.NetTcpV6ConnListErr
+}
+
+func (f *FakeSigar) GetNetUdpV6ConnList() (sigar.NetUdpV6ConnList, error) {
+	return f.NetUdpV6ConnList, f.NetUdpV6ConnListErr
+}
+
+func (f *FakeSigar) GetNetRawV6ConnList() (sigar.NetRawV6ConnList, error) {
+	return f.NetRawV6ConnList, f.NetRawV6ConnListErr
+}
+
+func (f *FakeSigar) GetNetConnList() (sigar.NetConnList, error) {
+	return f.NetConnList, f.NetConnListErr
+}
+
+func (f *FakeSigar) GetProcessList() (sigar.ProcessList, error) {
+	return f.ProcessList, f.ProcessListErr
+}
+
+func (f *FakeSigar) GetProcList() (sigar.ProcList, error) {
+	return f.ProcList, f.ProcListErr
+}
+
+func (f *FakeSigar) GetProcState(pid int) (sigar.ProcState, error) {
+	f.ProcStatePid = pid
+	return f.ProcState, f.ProcStateErr
+}
+
+func (f *FakeSigar) GetProcStatus(pid int) (sigar.ProcStatus, error) {
+	f.ProcStatusPid = pid
+	return f.ProcStatus, f.ProcStatusErr
+}
+
+func (f *FakeSigar) GetProcIo(pid int) (sigar.ProcIo, error) {
+	f.ProcIoPid = pid
+	return f.ProcIo, f.ProcIoErr
+}
+
+func (f *FakeSigar) GetProcMem(pid int) (sigar.ProcMem, error) {
+	f.ProcMemPid = pid
+	return f.ProcMem, f.ProcMemErr
+}
+
+func (f *FakeSigar) GetProcTime(pid int) (sigar.ProcTime, error) {
+	f.ProcTimePid = pid
+	return f.ProcTime, f.ProcTimeErr
+}
+
+func (f *FakeSigar) GetProcArgs(pid int) (sigar.ProcArgs, error) {
+	f.ProcArgsPid = pid
+	return f.ProcArgs, f.ProcArgsErr
+}
+
+func (f *FakeSigar) GetProcExe(pid int) (sigar.ProcExe, error) {
+	f.ProcExePid = pid
+	return f.ProcExe, f.ProcExeErr
+}
+
+func (f *FakeSigar) GetProcFd(pid int) (sigar.ProcFd, error) {
+	f.ProcFdPid = pid
+	return f.ProcFd, f.ProcFdErr
+}
+
+func (f *FakeSigar) GetProcLimits(pid int) (sigar.ProcLimits, error) {
+	f.ProcLimitsPid = pid
+	return f.ProcLimits, f.ProcLimitsErr
+}
+
+func (f *FakeSigar) GetProcFdUsageList() (sigar.ProcFdUsageList, error) {
+	return f.ProcFdUsageList, f.ProcFdUsageListErr
+}
+
+func (f *FakeSigar) GetProcFdList(pid int) (sigar.ProcFdList, error) {
+	f.ProcFdListPid = pid
+	return f.ProcFdList, f.ProcFdListErr
+}
+
+func (f *FakeSigar) GetSystemInfo() (sigar.SystemInfo, error) {
+	return f.SystemInfo, f.SystemInfoErr
+}
+
+func (f *FakeSigar) GetSystemDistribution() (sigar.SystemDistribution, error) {
+	return f.SystemDistribution, f.SystemDistributionErr
+}
+
+func (f *FakeSigar) GetKernelStat() (sigar.KernelStat, error) {
+	return f.KernelStat, f.KernelStatErr
+}
+
+func (f *FakeSigar) GetPressure() (sigar.Pressure, error) {
+	return f.Pressure, f.PressureErr
+}
+
+func (f *FakeSigar) GetMemInfo() (sigar.MemInfo, error) {
+	return f.MemInfo, f.MemInfoErr
+}
+
+func (f *FakeSigar) GetVmStat() (sigar.VmStat, error) {
+	return f.VmStat, f.VmStatErr
+}
+
+func (f *FakeSigar) GetSwapList() (sigar.SwapList, error) {
+	return f.SwapList, f.SwapListErr
+}
+
+func (f *FakeSigar) GetNumaNodeList() (sigar.NumaNodeList, error) {
+	return f.NumaNodeList, f.NumaNodeListErr
+}
+
+func (f *FakeSigar) GetCpuInfo() (sigar.CpuInfo, error) {
+	return f.CpuInfo, f.CpuInfoErr
+}
+
+func (f *FakeSigar) GetBlockDeviceList() (sigar.BlockDeviceList, error) {
+	return f.BlockDeviceList, f.BlockDeviceListErr
+}
+
+func (f *FakeSigar) GetMdArrayList() (sigar.MdArrayList, error) {
+	return f.MdArrayList, f.MdArrayListErr
+}
+
+func (f *FakeSigar) GetDeletedOpenFiles() (sigar.DeletedOpenFiles, error) {
+	return f.DeletedOpenFiles, f.DeletedOpenFilesErr
+}
+
+func (f *FakeSigar) GetSystemFd() (sigar.SystemFd, error) {
+	return f.SystemFd, f.SystemFdErr
+}
@@ -0,0 +1,284 @@
+package sigar
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ForecastNever is the time to full of a resource that isn't growing.
+const ForecastNever = time.Duration(math.MaxInt64)
+
+type ForecastOptions struct {
+	// How much history to fit the trend on. Defaults to 7 days.
+	Window time.Duration
+
+	// Samples closer than Resolution to the previous one replace it, which
+	// bounds the history kept per mount. Defaults to Window / 256.
+	Resolution time.Duration
+
+	// Fewer samples than this give no forecast. Defaults to 3.
+	MinSamples int
+
+	// Confidence level of the bounds, from 0 to 1. Defaults to 0.95.
+	Confidence float64
+}
+
+// FileSystemForecaster estimates when filesystems fill up, from periodic
+// FileSystemUsage samples of each mount. It is safe for concurrent use.
+//
+// The trend is the Theil-Sen slope, the median of the slopes between every
+// pair of samples, which outliers barely move. Drops much larger than the
+// typical change between samples, like a big delete or a log rotation, are
+// taken out of the series before fitting it.
+type FileSystemForecaster struct {
+	sync.Mutex
+	options ForecastOptions
+	mounts  map[string][]forecastSample
+}
+
+type forecastSample struct {
+	time  time.Time
+	usage FileSystemUsage
+}
+
+type FileSystemForecast struct {
+	Samples int           // Samples the forecast is based on
+	Span    time.Duration // Time between the oldest and newest of them
+	Bytes   UsageForecast
+	Files   UsageForecast // Inodes. Never full for filesystems without a fixed number of them.
+}
+
+type UsageForecast struct {
+	Growth     float64       // Per second, bytes or inodes. Negative when shrinking.
+	TimeToFull time.Duration // From the newest sample, or ForecastNever
+	Earliest   time.Duration // Confidence bounds of TimeToFull
+	Latest     time.Duration
+}
+
+func NewFileSystemForecaster(options ForecastOptions) *FileSystemForecaster {
+	if options.Window <= 0 {
+		options.Window = 7 * 24 * time.Hour
+	}
+	if options.Resolution <= 0 {
+		options.Resolution = options.Window / 256
+	}
+	if options.MinSamples < 3 {
+		options.MinSamples = 3
+	}
+	if options.Confidence <= 0 || options.Confidence >= 1 {
+		options.Confidence = 0.95
+	}
+	return &FileSystemForecaster{
+		options: options,
+		mounts:  make(map[string][]forecastSample),
+	}
+}
+
+// Add records the usage of the filesystem mounted on dirName at time t.
+// Samples must be added in time order.
+func (self *FileSystemForecaster) Add(dirName string, t time.Time, usage FileSystemUsage) {
+	self.Lock()
+	defer self.Unlock()
+
+	// The newest sample is provisional until it is Resolution apart from the
+	// one before it
+	samples := self.mounts[dirName]
+	sample := forecastSample{time: t, usage: usage}
+	if n := len(samples); n > 1 && samples[n-1].time.Sub(samples[n-2].time) < self.options.Resolution {
+		samples[n-1] = sample
+	} else {
+		samples = append(samples, sample)
+	}
+
+	oldest := 0
+	for oldest < len(samples) && t.Sub(samples[oldest].time) > self.options.Window {
+		oldest++
+	}
+	self.mounts[dirName] = samples[oldest:]
+}
+
+// Forget drops the history of a mount, e.g. once it is unmounted.
+func (self *FileSystemForecaster) Forget(dirName string) {
+	self.Lock()
+	defer self.Unlock()
+	delete(self.mounts, dirName)
+}
+
+// Forecast returns false until the mount has MinSamples samples.
+func (self *FileSystemForecaster) Forecast(dirName string) (FileSystemForecast, bool) {
+	self.Lock()
+	defer self.Unlock()
+
+	samples := self.mounts[dirName]
+	if len(samples) < self.options.MinSamples {
+		return FileSystemForecast{}, false
+	}
+	first, last := samples[0], samples[len(samples)-1]
+	if !last.time.After(first.time) {
+		return FileSystemForecast{}, false
+	}
+
+	usedBytes := func(usage FileSystemUsage) float64 { return float64(usage.Used) }
+	usedFiles := func(usage FileSystemUsage) float64 { return float64(usage.UsedFiles()) }
+
+	forecast := FileSystemForecast{
+		Samples: len(samples),
+		Span:    last.time.Sub(first.time),
+		Bytes:   self.forecastUsage(samples, usedBytes, float64(last.usage.Total), float64(last.usage.Avail)),
+		Files:   UsageForecast{TimeToFull: ForecastNever, Earliest: ForecastNever, Latest: ForecastNever},
+	}
+	if last.usage.Files > 0 {
+		forecast.Files = self.forecastUsage(samples, usedFiles, float64(last.usage.Files), float64(last.usage.FreeFiles))
+	}
+	return forecast, true
+}
+
+func (self *FileSystemForecaster) forecastUsage(samples []forecastSample, used func(FileSystemUsage) float64, total, remaining float64) UsageForecast {
+	values := withoutDrops(samples, used, total)
+
+	slopes := make([]float64, 0, len(samples)*(len(samples)-1)/2)
+	for i := range samples {
+		for j := i + 1; j < len(samples); j++ {
+			seconds := samples[j].time.Sub(samples[i].time).Seconds()
+			if seconds <= 0 {
+				continue
+			}
+			slopes = append(slopes, (values[j]-values[i])/seconds)
+		}
+	}
+	if len(slopes) == 0 {
+		return UsageForecast{TimeToFull: ForecastNever, Earliest: ForecastNever, Latest: ForecastNever}
+	}
+	sort.Float64s(slopes)
+
+	lower, upper := senConfidence(slopes, len(samples), self.options.Confidence)
+	growth := median(slopes)
+	return UsageForecast{
+		Growth:     growth,
+		TimeToFull: timeToFull(remaining, growth),
+		Earliest:   timeToFull(remaining, upper),
+		Latest:     timeToFull(remaining, lower),
+	}
+}
+
+// Usage of each sample, adding back the drops that look like a delete or a
+// log rotation, so that the series continues where it was before them
+func withoutDrops(samples []forecastSample, used func(FileSystemUsage) float64, total float64) []float64 {
+	changes := make([]float64, 0, len(samples)-1)
+	for i := 1; i < len(samples); i++ {
+		changes = append(changes, used(samples[i].usage)-used(samples[i-1].usage))
+	}
+
+	// Most changes are zero on a quiet mount, so the typical change alone
+	// would make every decrease look large
+	threshold := math.Max(4*medianAbs(changes), 4*medianDeviation(changes))
+	threshold = math.Max(threshold, total/100)
+
+	values := make([]float64, len(samples))
+	offset := 0.0
+	for i, sample := range samples {
+		if i > 0 && isDelete(samples, used, i, threshold) {
+			offset -= changes[i-1]
+		}
+		values[i] = used(sample.usage) + offset
+	}
+	return values
+}
+
+// A drop to sample i is a delete when it is over threshold and usage doesn't
+// climb back afterwards. Temporary files that come and go are churn, and so
+// is a drop too close to the newest sample to tell yet.
+func isDelete(samples []forecastSample, used func(FileSystemUsage) float64, i int, threshold float64) bool {
+	before := used(samples[i-1].usage)
+	drop := before - used(samples[i].usage)
+	if drop <= threshold {
+		return false
+	}
+
+	first, last := samples[0].time, samples[len(samples)-1].time
+	if last.Sub(samples[i].time) < last.Sub(first)/4 {
+		return false
+	}
+	for _, later := range samples[i+1:] {
+		if used(later.usage) >= before-drop/2 {
+			return false
+		}
+	}
+	return true
+}
+
+// Median of the absolute values
+func medianAbs(values []float64) float64 {
+	abs := make([]float64, len(values))
+	for i, value := range values {
+		abs[i] = math.Abs(value)
+	}
+	sort.Float64s(abs)
+	return median(abs)
+}
+
+// Median absolute deviation from the median
+func medianDeviation(values []float64) float64 {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	mid := median(sorted)
+
+	deviations := make([]float64, len(values))
+	for i, value := range values {
+		deviations[i] = value - mid
+	}
+	return medianAbs(deviations)
+}
+
+// Bounds of the Theil-Sen slope, from the ranks of the sorted pairwise slopes
+// given by the normal approximation of Kendall's tau
+func senConfidence(slopes []float64, n int, confidence float64) (float64, float64) {
+	z := normalQuantile(0.5 + confidence/2)
+	c := z * math.Sqrt(float64(n*(n-1)*(2*n+5))/18)
+	count := float64(len(slopes))
+
+	lower := int(math.Floor((count - c) / 2))
+	upper := int(math.Ceil((count + c) / 2))
+	if lower < 0 {
+		lower = 0
+	}
+	if upper > len(slopes)-1 {
+		upper = len(slopes) - 1
+	}
+	return slopes[lower], slopes[upper]
+}
+
+func median(sorted []float64) float64 {
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
+
+func timeToFull(remaining, growth float64) time.Duration {
+	if growth <= 0 {
+		return ForecastNever
+	}
+	seconds := remaining / growth
+	if seconds >= float64(math.MaxInt64)/float64(time.Second) {
+		return ForecastNever
+	}
+	return time.Duration(seconds * float64(time.Second))
+}
+
+// Quantile of the standard normal distribution, by bisection of math.Erf
+func normalQuantile(p float64) float64 {
+	low, high := -10.0, 10.0
+	for i := 0; i < 100; i++ {
+		mid := (low + high) / 2
+		if 0.5*(1+math.Erf(mid/math.Sqrt2)) < p {
+			low = mid
+		} else {
+			high = mid
+		}
+	}
+	return (low + high) / 2
+}
@@ -0,0 +1,125 @@
+package sigar_test
+
+import (
+	"time"
+
+	. "github.com/scalingdata/ginkgo"
+	. "github.com/scalingdata/gomega"
+
+	sigar "github.com/scalingdata/gosigar"
+)
+
+var _ = Describe("FileSystemForecaster", func() {
+	const gb = 1 << 30
+
+	var (
+		forecaster *sigar.FileSystemForecaster
+		start      time.Time
+	)
+
+	// A 100GB filesystem with 1M inodes
+	usage := func(usedBytes, usedFiles uint64) sigar.FileSystemUsage {
+		return sigar.FileSystemUsage{
+			Total:     100 * gb,
+			Used:      usedBytes,
+			Free:      100*gb - usedBytes,
+			Avail:     100*gb - usedBytes,
+			Files:     1000000,
+			FreeFiles: 1000000 - usedFiles,
+		}
+	}
+
+	BeforeEach(func() {
+		forecaster = sigar.NewFileSystemForecaster(sigar.ForecastOptions{Resolution: time.Minute})
+		start = time.Date(2016, 1, 1, 0, 0, 0, 0, time.UTC)
+	})
+
+	It("needs a few samples before forecasting", func() {
+		forecaster.Add("/", start, usage(50*gb, 0))
+		forecaster.Add("/", start.Add(time.Hour), usage(51*gb, 0))
+
+		_, ok := forecaster.Forecast("/")
+		Expect(ok).To(BeFalse())
+		_, ok = forecaster.Forecast("/unknown")
+		Expect(ok).To(BeFalse())
+	})
+
+	It("estimates the time until bytes and inodes run out", func() {
+		// 1GB and 10000 inodes a day
+		for day := 0; day <= 10; day++ {
+			t := start.Add(time.Duration(day) * 24 * time.Hour)
+			forecaster.Add("/", t, usage(uint64(40+day)*gb, uint64(500000+10000*day)))
+		}
+
+		forecast, ok := forecaster.Forecast("/")
+		Expect(ok).To(BeTrue())
+		Expect(forecast.Samples).To(Equal(8)) // Older samples are outside the 7 day window
+		Expect(forecast.Span).To(Equal(7 * 24 * time.Hour))
+
+		Expect(forecast.Bytes.Growth).To(BeNumerically("~", float64(gb)/86400, 1))
+		Expect(forecast.Bytes.TimeToFull).To(BeNumerically("~", 50*24*time.Hour, time.Minute))
+		Expect(forecast.Bytes.Earliest).To(BeNumerically("<=", forecast.Bytes.TimeToFull))
+		Expect(forecast.Bytes.Latest).To(BeNumerically(">=", forecast.Bytes.TimeToFull))
+		Expect(forecast.Files.TimeToFull).To(BeNumerically("~", 40*24*time.Hour, time.Minute))
+	})
+
+	It("keeps the trend through a delete", func() {
+		used := uint64(40 * gb)
+		for hour := 0; hour <= 48; hour++ {
+			if hour == 30 {
+				used -= 20 * gb // Log rotation
+			}
+			forecaster.Add("/var", start.Add(time.Duration(hour)*time.Hour), usage(used, 0))
+			used += gb / 10
+		}
+
+		forecast, ok := forecaster.Forecast("/var")
+		Expect(ok).To(BeTrue())
+		Expect(forecast.Bytes.Growth).To(BeNumerically("~", float64(gb)/10/3600, 1))
+		Expect(forecast.Bytes.Latest).To(BeNumerically("<", forecast.Bytes.TimeToFull*21/20))
+	})
+
+	It("ignores temporary files that come and go on a flat filesystem", func() {
+		for hour := 0; hour < 48; hour++ {
+			used := uint64(50 * gb)
+			if hour%6 == 0 {
+				used += 5 * gb // A temporary file, deleted within the hour
+			}
+			forecaster.Add("/tmp", start.Add(time.Duration(hour)*time.Hour), usage(used, 0))
+		}
+
+		forecast, ok := forecaster.Forecast("/tmp")
+		Expect(ok).To(BeTrue())
+		Expect(forecast.Bytes.Growth).To(BeZero())
+		Expect(forecast.Bytes.TimeToFull).To(Equal(sigar.ForecastNever))
+	})
+
+	It("never fills a shrinking filesystem, or one without an inode limit", func() {
+		for hour := 0; hour < 5; hour++ {
+			u := usage(uint64(50-hour)*gb, 0)
+			u.Files, u.FreeFiles = 0, 0
+			forecaster.Add("/", start.Add(time.Duration(hour)*time.Hour), u)
+		}
+
+		forecast, ok := forecaster.Forecast("/")
+		Expect(ok).To(BeTrue())
+		Expect(forecast.Bytes.Growth).To(BeNumerically("<", 0))
+		Expect(forecast.Bytes.TimeToFull).To(Equal(sigar.ForecastNever))
+		Expect(forecast.Files.TimeToFull).To(Equal(sigar.ForecastNever))
+	})
+
+	It("thins out samples closer than the resolution", func() {
+		for second := 0; second < 600; second += 10 {
+			forecaster.Add("/", start.Add(time.Duration(second)*time.Second), usage(uint64(second)*gb/600, 0))
+		}
+
+		forecast, ok := forecaster.Forecast("/")
+		Expect(ok).To(BeTrue())
+		Expect(forecast.Samples).To(Equal(11))
+		Expect(forecast.Span).To(Equal(590 * time.Second))
+
+		forecaster.Forget("/")
+		_, ok = forecaster.Forecast("/")
+		Expect(ok).To(BeFalse())
+	})
+})
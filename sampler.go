@@ -0,0 +1,403 @@
+package sigar
+
+import (
+	"math/rand"
+	"strconv"
+	"time"
+)
+
+// Metric selects the collectors run by Sample. Metrics can be combined with |.
+type Metric uint
+
+const (
+	MetricCpu Metric = 1 << iota
+	MetricCpuList
+	MetricLoadAverage
+	MetricMem
+	MetricSwap
+	MetricDiskList
+	MetricNetIfaceList
+	MetricNetProtoV4Stats
+	MetricNetProtoV6Stats
+	MetricProcessList
+	MetricKernelStat
+	MetricPressure
+	MetricVmStat
+	MetricMdArrayList
+
+	MetricAll = MetricCpu | MetricCpuList | MetricLoadAverage | MetricMem | MetricSwap |
+		MetricDiskList | MetricNetIfaceList | MetricNetProtoV4Stats | MetricNetProtoV6Stats |
+		MetricProcessList | MetricKernelStat | MetricPressure | MetricVmStat | MetricMdArrayList
+)
+
+var metricNames = map[Metric]string{
+	MetricCpu:             "Cpu",
+	MetricCpuList:         "CpuList",
+	MetricLoadAverage:     "LoadAverage",
+	MetricMem:             "Mem",
+	MetricSwap:            "Swap",
+	MetricDiskList:        "DiskList",
+	MetricNetIfaceList:    "NetIfaceList",
+	MetricNetProtoV4Stats: "NetProtoV4Stats",
+	MetricNetProtoV6Stats: "NetProtoV6Stats",
+	MetricProcessList:     "ProcessList",
+	MetricKernelStat:      "KernelStat",
+	MetricPressure:        "Pressure",
+	MetricVmStat:          "VmStat",
+	MetricMdArrayList:     "MdArrayList",
+}
+
+func (self Metric) String() string {
+	if name, ok := metricNames[self]; ok {
+		return name
+	}
+	return "Metric(" + strconv.FormatUint(uint64(self), 10) + ")"
+}
+
+// What to do when collecting a snapshot took longer than the interval
+type MissedTickPolicy int
+
+const (
+	// Skip the ticks that passed and collect at the next one, staying aligned
+	// to the original schedule. This is how time.Ticker behaves.
+	MissedTickSkip MissedTickPolicy = iota
+	// Collect again one interval after the late collection finished
+	MissedTickDelay
+)
+
+// What to do with a snapshot when the consumer hasn't received the previous ones
+type SlowConsumerPolicy int
+
+const (
+	// Drop the new snapshot, as CollectCpuStats does
+	SlowConsumerDropNewest SlowConsumerPolicy = iota
+	// Drop the oldest buffered snapshot, so the consumer always gets the latest
+	SlowConsumerDropOldest
+	// Wait for the consumer. Ticks that pass meanwhile are handled by MissedTickPolicy.
+	SlowConsumerBlock
+)
+
+type SamplerOptions struct {
+	Interval time.Duration
+	Metrics  Metric // Defaults to MetricAll
+
+	// Delay each collection by a random duration up to Jitter, so that many
+	// hosts started together don't all read at the same instant
+	Jitter time.Duration
+
+	MissedTicks  MissedTickPolicy
+	SlowConsumer SlowConsumerPolicy
+	BufferSize   int // Snapshots buffered for the consumer. Defaults to 1.
+}
+
+// Snapshot holds every metric collected at one tick. Delta fields hold the
+// change of each counter since the previous snapshot, and are empty in the
+// first one. Deltas are always from the previous collection, even when
+// snapshots in between were dropped; use the raw values to bridge the gap.
+type Snapshot struct {
+	Time     time.Time
+	Interval time.Duration // Time since the previous snapshot was collected
+	Metrics  Metric        // The metrics that were collected
+	Missed   int           // Ticks skipped since the previous snapshot
+	Dropped  int           // Snapshots the consumer didn't receive since the previous one
+
+	// Why a collector failed. The metric may still hold partial data, e.g.
+	// when ProcessList returns a *PartialError.
+	Errors map[Metric]error
+
+	Cpu             Cpu
+	CpuDelta        Cpu
+	CpuList         CpuList
+	CpuListDelta    CpuList
+	LoadAverage     LoadAverage
+	KernelStat      KernelStat
+	KernelStatDelta KernelStat
+	Pressure        Pressure
+	PressureDelta   Pressure
+	Mem             Mem
+	Swap            Swap
+	VmStat          VmStat
+	VmStatDelta     VmStat
+
+	DiskList      DiskList
+	DiskListDelta DiskList
+	MdArrayList   MdArrayList
+
+	NetIfaceList      NetIfaceList
+	NetIfaceListDelta NetIfaceList // Interfaces that are present in both snapshots
+
+	NetProtoV4Stats      NetProtoV4Stats
+	NetProtoV4StatsDelta NetProtoV4Stats
+	NetProtoV6Stats      NetProtoV6Stats
+	NetProtoV6StatsDelta NetProtoV6Stats
+
+	ProcessList   ProcessList
+	ProcessDeltas map[int]ProcessDelta // Keyed by pid, for processes present in both snapshots
+}
+
+// The change of a process's counters between two snapshots
+type ProcessDelta struct {
+	ProcIo   ProcIo
+	ProcTime ProcTime // User, Sys and Total deltas, and the CPU percentages over the interval
+}
+
+// Sample periodically collects the selected metrics from s, sending one
+// Snapshot per tick. The first snapshot is sent immediately. Send on or
+// close the returned stop channel to stop sampling; the snapshot channel is
+// then closed.
+func Sample(s Sigar, options SamplerOptions) (<-chan Snapshot, chan<- struct{}) {
+	if options.Metrics == 0 {
+		options.Metrics = MetricAll
+	}
+	if options.BufferSize <= 0 {
+		options.BufferSize = 1
+	}
+	if options.Interval <= 0 {
+		options.Interval = time.Second
+	}
+
+	snapshotCh := make(chan Snapshot, options.BufferSize)
+	stopCh := make(chan struct{})
+
+	go func() {
+		defer close(snapshotCh)
+
+		var previous *Snapshot
+		missed, dropped := 0, 0
+		next := time.Now()
+		// The global source is unseeded before Go 1.20, so every host would
+		// draw the same delays
+		random := rand.New(rand.NewSource(next.UnixNano()))
+
+		for {
+			if !sleepUntil(next, jitter(random, options.Jitter), stopCh) {
+				return
+			}
+
+			snapshot := collectSnapshot(s, options.Metrics, previous)
+			snapshot.Missed = missed
+			snapshot.Dropped = dropped
+			previous = &snapshot
+
+			sent, ok := sendSnapshot(snapshotCh, snapshot, options.SlowConsumer, stopCh)
+			if !ok {
+				return
+			}
+			if sent {
+				dropped = 0
+			} else {
+				dropped++
+			}
+
+			next, missed = nextTick(next, time.Now(), options.Interval, options.MissedTicks)
+		}
+	}()
+
+	return snapshotCh, stopCh
+}
+
+func jitter(random *rand.Rand, max time.Duration) time.Duration {
+	if max <= 0 {
+		return 0
+	}
+	return time.Duration(random.Int63n(int64(max)))
+}
+
+// Wait until the tick at next plus jitter. Returns false if stopped first.
+func sleepUntil(next time.Time, jitter time.Duration, stopCh <-chan struct{}) bool {
+	timer := time.NewTimer(next.Add(jitter).Sub(time.Now()))
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true
+	case <-stopCh:
+		return false
+	}
+}
+
+// Schedule the tick after last, now that collecting it finished at now.
+// Returns the next tick and how many ticks were missed.
+func nextTick(last, now time.Time, interval time.Duration, policy MissedTickPolicy) (time.Time, int) {
+	next := last.Add(interval)
+	if next.After(now) {
+		return next, 0
+	}
+
+	missed := int(now.Sub(next)/interval) + 1
+	if policy == MissedTickDelay {
+		return now.Add(interval), missed
+	}
+	return next.Add(time.Duration(missed) * interval), missed
+}
+
+// Send snapshot according to policy. sent is false if it was dropped, and
+// ok is false if sampling was stopped while blocked.
+func sendSnapshot(snapshotCh chan Snapshot, snapshot Snapshot, policy SlowConsumerPolicy, stopCh <-chan struct{}) (sent bool, ok bool) {
+	switch policy {
+	case SlowConsumerBlock:
+		select {
+		case snapshotCh <- snapshot:
+			return true, true
+		case <-stopCh:
+			return false, false
+		}
+
+	case SlowConsumerDropOldest:
+		for {
+			select {
+			case snapshotCh <- snapshot:
+				return true, true
+			default:
+			}
+			// Make room, unless the consumer just did
+			select {
+			case <-snapshotCh:
+				snapshot.Dropped++
+			default:
+			}
+		}
+
+	default:
+		select {
+		case snapshotCh <- snapshot:
+			return true, true
+		default:
+			return false, true
+		}
+	}
+}
+
+func collectSnapshot(s Sigar, metrics Metric, previous *Snapshot) Snapshot {
+	snapshot := Snapshot{
+		Time:    time.Now(),
+		Metrics: metrics,
+		Errors:  make(map[Metric]error),
+	}
+
+	collect := func(metric Metric, get func() error) {
+		if metrics&metric == 0 {
+			return
+		}
+		if err := get(); err != nil {
+			snapshot.Errors[metric] = err
+		}
+	}
+
+	var err error
+	collect(MetricCpu, func() error { snapshot.Cpu, err = s.GetCpu(); return err })
+	collect(MetricCpuList, func() error { snapshot.CpuList, err = s.GetCpuList(); return err })
+	collect(MetricLoadAverage, func() error { snapshot.LoadAverage, err = s.GetLoadAverage(); return err })
+	collect(MetricMem, func() error { snapshot.Mem, err = s.GetMem(); return err })
+	collect(MetricSwap, func() error { snapshot.Swap, err = s.GetSwap(); return err })
+	collect(MetricDiskList, func() error { snapshot.DiskList, err = s.GetDiskList(); return err })
+	collect(MetricNetIfaceList, func() error { snapshot.NetIfaceList, err = s.GetNetIfaceList(); return err })
+	collect(MetricNetProtoV4Stats, func() error { snapshot.NetProtoV4Stats, err = s.GetNetProtoV4Stats(); return err })
+	collect(MetricNetProtoV6Stats, func() error { snapshot.NetProtoV6Stats, err = s.GetNetProtoV6Stats(); return err })
+	collect(MetricProcessList, func() error { snapshot.ProcessList, err = s.GetProcessList(); return err })
+	collect(MetricKernelStat, func() error { snapshot.KernelStat, err = s.GetKernelStat(); return err })
+	collect(MetricPressure, func() error { snapshot.Pressure, err = s.GetPressure(); return err })
+	collect(MetricVmStat, func() error { snapshot.VmStat, err = s.GetVmStat(); return err })
+	collect(MetricMdArrayList, func() error { snapshot.MdArrayList, err = s.GetMdArrayList(); return err })
+
+	if previous != nil {
+		snapshot.Interval = snapshot.Time.Sub(previous.Time)
+		snapshot.computeDeltas(previous)
+	}
+	return snapshot
+}
+
+// Compute the deltas of every metric collected without error in both snapshots
+func (self *Snapshot) computeDeltas(previous *Snapshot) {
+	has := func(metric Metric) bool {
+		if self.Metrics&metric == 0 || previous.Metrics&metric == 0 {
+			return false
+		}
+		// Partial lists still hold comparable entries
+		return (self.Errors[metric] == nil || IsPartial(self.Errors[metric])) &&
+			(previous.Errors[metric] == nil || IsPartial(previous.Errors[metric]))
+	}
+
+	if has(MetricCpu) {
+		self.CpuDelta = self.Cpu.Delta(previous.Cpu)
+	}
+
+	if has(MetricCpuList) {
+		self.CpuListDelta = self.CpuList.Delta(previous.CpuList)
+	}
+
+	if has(MetricKernelStat) {
+		self.KernelStatDelta = self.KernelStat.Delta(previous.KernelStat)
+	}
+
+	if has(MetricPressure) {
+		self.PressureDelta = self.Pressure.Delta(previous.Pressure)
+	}
+
+	if has(MetricVmStat) {
+		self.VmStatDelta = self.VmStat.Delta(previous.VmStat)
+	}
+
+	if has(MetricDiskList) {
+		self.DiskListDelta.List = make(map[string]DiskIo)
+		for name, disk := range self.DiskList.List {
+			if previousDisk, ok := previous.DiskList.List[name]; ok {
+				self.DiskListDelta.List[name] = disk.Delta(previousDisk)
+			}
+		}
+	}
+
+	if has(MetricNetIfaceList) {
+		previousIfaces := make(map[string]NetIface)
+		for _, iface := range previous.NetIfaceList.List {
+			previousIfaces[iface.Name] = iface
+		}
+		for _, iface := range self.NetIfaceList.List {
+			if previousIface, ok := previousIfaces[iface.Name]; ok {
+				self.NetIfaceListDelta.List = append(self.NetIfaceListDelta.List, iface.Delta(previousIface))
+			}
+		}
+	}
+
+	if has(MetricNetProtoV4Stats) {
+		self.NetProtoV4StatsDelta = self.NetProtoV4Stats.Delta(previous.NetProtoV4Stats)
+	}
+	if has(MetricNetProtoV6Stats) {
+		self.NetProtoV6StatsDelta = self.NetProtoV6Stats.Delta(previous.NetProtoV6Stats)
+	}
+
+	if has(MetricProcessList) {
+		previousProcesses := make(map[int]Process)
+		for _, process := range previous.ProcessList.List {
+			previousProcesses[process.ProcState.Pid] = process
+		}
+
+		self.ProcessDeltas = make(map[int]ProcessDelta)
+		for _, process := range self.ProcessList.List {
+			previousProcess, ok := previousProcesses[process.ProcState.Pid]
+			// A different start time means the pid was reused
+			if !ok || previousProcess.ProcTime.StartTime != process.ProcTime.StartTime {
+				continue
+			}
+
+			procTime := ProcTime{
+				CollectionTime: process.ProcTime.CollectionTime,
+				StartTime:      process.ProcTime.StartTime,
+				User:           counterDelta(process.ProcTime.User, previousProcess.ProcTime.User),
+				Sys:            counterDelta(process.ProcTime.Sys, previousProcess.ProcTime.Sys),
+				Total:          counterDelta(process.ProcTime.Total, previousProcess.ProcTime.Total),
+			}
+			current := process.ProcTime
+			if current.CalculateCpuPercent(&previousProcess.ProcTime) == nil {
+				procTime.PercentUserTime = current.PercentUserTime
+				procTime.PercentSysTime = current.PercentSysTime
+				procTime.PercentTotalTime = current.PercentTotalTime
+			}
+
+			self.ProcessDeltas[process.ProcState.Pid] = ProcessDelta{
+				ProcIo:   process.ProcIo.Delta(previousProcess.ProcIo),
+				ProcTime: procTime,
+			}
+		}
+	}
+}
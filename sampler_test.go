@@ -0,0 +1,142 @@
+package sigar_test
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	. "github.com/scalingdata/ginkgo"
+	. "github.com/scalingdata/gomega"
+
+	sigar "github.com/scalingdata/gosigar"
+	"github.com/scalingdata/gosigar/fakes"
+)
+
+// Returns counters that grow by a fixed amount on every collection
+type countingSigar struct {
+	*fakes.FakeSigar
+
+	sync.Mutex
+	calls int
+	delay time.Duration
+}
+
+func (self *countingSigar) GetCpu() (sigar.Cpu, error) {
+	self.Lock()
+	defer self.Unlock()
+	self.calls++
+	time.Sleep(self.delay)
+	return sigar.Cpu{User: uint64(100 * self.calls), Idle: uint64(300 * self.calls)}, nil
+}
+
+func (self *countingSigar) GetNetIfaceList() (sigar.NetIfaceList, error) {
+	self.Lock()
+	defer self.Unlock()
+	return sigar.NetIfaceList{List: []sigar.NetIface{
+		{Name: "eth0", MTU: 1500, RecvBytes: uint64(1000 * self.calls)},
+	}}, nil
+}
+
+func (self *countingSigar) GetDiskList() (sigar.DiskList, error) {
+	self.Lock()
+	defer self.Unlock()
+	return sigar.DiskList{List: map[string]sigar.DiskIo{
+		"sda": {ReadOps: uint64(10 * self.calls)},
+	}}, nil
+}
+
+var _ = Describe("Sample", func() {
+	var countingFake *countingSigar
+
+	BeforeEach(func() {
+		countingFake = &countingSigar{FakeSigar: fakes.NewFakeSigar()}
+	})
+
+	It("immediately sends raw values, then deltas at every tick", func() {
+		snapshots, stop := sigar.Sample(countingFake, sigar.SamplerOptions{
+			Interval: 20 * time.Millisecond,
+			Metrics:  sigar.MetricCpu | sigar.MetricNetIfaceList | sigar.MetricDiskList,
+		})
+		defer close(stop)
+
+		first := <-snapshots
+		Expect(first.Cpu.User).To(Equal(uint64(100)))
+		Expect(first.CpuDelta).To(Equal(sigar.Cpu{}))
+		Expect(first.Interval).To(Equal(time.Duration(0)))
+		Expect(first.Errors).To(BeEmpty())
+
+		second := <-snapshots
+		Expect(second.Cpu.User).To(Equal(uint64(200)))
+		Expect(second.CpuDelta).To(Equal(sigar.Cpu{User: 100, Idle: 300}))
+		Expect(second.Interval).To(BeNumerically(">", 0))
+		Expect(second.NetIfaceListDelta.List).To(Equal([]sigar.NetIface{{Name: "eth0", MTU: 1500, RecvBytes: 1000}}))
+		Expect(second.DiskListDelta.List["sda"].ReadOps).To(Equal(uint64(10)))
+	})
+
+	It("records errors and skips deltas of failed collectors", func() {
+		countingFake.MemErr = errors.New("boom")
+
+		snapshots, stop := sigar.Sample(countingFake, sigar.SamplerOptions{
+			Interval: 20 * time.Millisecond,
+			Metrics:  sigar.MetricCpu | sigar.MetricMem,
+		})
+		defer close(stop)
+
+		<-snapshots
+		second := <-snapshots
+		Expect(second.Errors).To(HaveLen(1))
+		Expect(second.Errors[sigar.MetricMem]).To(MatchError("boom"))
+		Expect(second.CpuDelta.User).To(Equal(uint64(100)))
+	})
+
+	It("counts ticks missed by slow collections", func() {
+		countingFake.delay = 30 * time.Millisecond
+
+		snapshots, stop := sigar.Sample(countingFake, sigar.SamplerOptions{
+			Interval: 10 * time.Millisecond,
+			Metrics:  sigar.MetricCpu,
+		})
+		defer close(stop)
+
+		<-snapshots
+		Expect((<-snapshots).Missed).To(BeNumerically(">=", 2))
+	})
+
+	It("drops snapshots for slow consumers and counts them", func() {
+		snapshots, stop := sigar.Sample(countingFake, sigar.SamplerOptions{
+			Interval: 10 * time.Millisecond,
+			Metrics:  sigar.MetricCpu,
+		})
+		defer close(stop)
+
+		time.Sleep(50 * time.Millisecond)
+		Expect((<-snapshots).Cpu.User).To(Equal(uint64(100)))
+		Expect((<-snapshots).Dropped).To(BeNumerically(">", 0))
+	})
+
+	It("keeps the latest snapshot when dropping the oldest", func() {
+		snapshots, stop := sigar.Sample(countingFake, sigar.SamplerOptions{
+			Interval:     10 * time.Millisecond,
+			Metrics:      sigar.MetricCpu,
+			SlowConsumer: sigar.SlowConsumerDropOldest,
+		})
+		defer close(stop)
+
+		time.Sleep(50 * time.Millisecond)
+		latest := <-snapshots
+		Expect(latest.Cpu.User).To(BeNumerically(">", 100))
+		Expect(latest.Dropped).To(BeNumerically(">", 0))
+	})
+
+	It("closes the snapshot channel when stopped", func() {
+		snapshots, stop := sigar.Sample(countingFake, sigar.SamplerOptions{
+			Interval:     10 * time.Millisecond,
+			Metrics:      sigar.MetricCpu,
+			SlowConsumer: sigar.SlowConsumerBlock,
+		})
+
+		<-snapshots
+		stop <- struct{}{}
+		Eventually(snapshots).Should(BeClosed())
+	})
+})
@@ -0,0 +1,181 @@
+package sigar
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+)
+
+// TimeoutError is returned by the context-aware collectors when the context
+// deadline passes while a system call is still blocked, e.g. a statfs on a
+// dead NFS mount or a read of /proc/<pid> for a process stuck in the kernel.
+type TimeoutError struct {
+	Op   string // The blocking operation, e.g. "statfs" or "read"
+	Path string // The mount point or file the operation was stuck on
+	Pid  int    // The process being read, if any
+}
+
+func (self *TimeoutError) Error() string {
+	if self.Pid != 0 {
+		return fmt.Sprintf("Timed out in %s of %s for pid %d", self.Op, self.Path, self.Pid)
+	}
+	return fmt.Sprintf("Timed out in %s of %s", self.Op, self.Path)
+}
+
+// Timeout reports true, matching the net.Error convention.
+func (self *TimeoutError) Timeout() bool {
+	return true
+}
+
+// A blocking call that may outlive the context of the collector that started it.
+type blockingCall struct {
+	done chan struct{}
+	val  interface{}
+	err  error
+}
+
+// Tracks blocking calls that are still running. A call that is stuck (e.g. on
+// a hung mount) is shared with every later caller for the same operation and
+// path instead of starting another goroutine, so at most one goroutine per
+// mount or pid can leak.
+type blockingCallGroup struct {
+	sync.Mutex
+	calls map[string]*blockingCall
+}
+
+var blockingCalls = &blockingCallGroup{}
+
+// Run fn, giving up when ctx is done. Returns a *TimeoutError naming op, path
+// and pid if the deadline passes first, or ctx.Err() if ctx is cancelled.
+func (self *blockingCallGroup) do(ctx context.Context, op, path string, pid int, fn func() (interface{}, error)) (interface{}, error) {
+	// Contexts that can never be done don't need the extra goroutine
+	if ctx.Done() == nil {
+		return fn()
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, contextError(err, op, path, pid)
+	}
+
+	key := op + " " + path + " " + strconv.Itoa(pid)
+
+	self.Lock()
+	if self.calls == nil {
+		self.calls = make(map[string]*blockingCall)
+	}
+	call, ok := self.calls[key]
+	if !ok {
+		call = &blockingCall{done: make(chan struct{})}
+		self.calls[key] = call
+		go func() {
+			call.val, call.err = fn()
+
+			self.Lock()
+			delete(self.calls, key)
+			self.Unlock()
+			close(call.done)
+		}()
+	}
+	self.Unlock()
+
+	select {
+	case <-call.done:
+		return call.val, call.err
+	case <-ctx.Done():
+		return nil, contextError(ctx.Err(), op, path, pid)
+	}
+}
+
+func contextError(err error, op, path string, pid int) error {
+	if err == context.DeadlineExceeded {
+		return &TimeoutError{Op: op, Path: path, Pid: pid}
+	}
+	return err
+}
+
+// GetContext is like Get, but gives up with a *TimeoutError when the
+// deadline of ctx passes before statfs returns.
+func (self *FileSystemUsage) GetContext(ctx context.Context, path string) error {
+	usage, err := blockingCalls.do(ctx, "statfs", path, 0, func() (interface{}, error) {
+		usage := FileSystemUsage{}
+		err := usage.Get(path)
+		return usage, err
+	})
+	if err != nil {
+		return err
+	}
+	*self = usage.(FileSystemUsage)
+	return nil
+}
+
+func (c *ConcreteSigar) GetFileSystemUsageContext(ctx context.Context, path string) (FileSystemUsage, error) {
+	f := FileSystemUsage{}
+	err := f.GetContext(ctx, path)
+	return f, err
+}
+
+func (c *ConcreteSigar) GetDirUsageContext(ctx context.Context, path string, options DirUsageOptions) (DirUsage, error) {
+	d := DirUsage{}
+	err := d.GetContext(ctx, path, options)
+	return d, err
+}
+
+func (c *ConcreteSigar) GetFileSystemListContext(ctx context.Context) (FileSystemList, error) {
+	l := FileSystemList{}
+	err := l.GetContext(ctx)
+	return l, err
+}
+
+func (c *ConcreteSigar) GetProcessListContext(ctx context.Context) (ProcessList, error) {
+	l := ProcessList{}
+	err := l.GetContext(ctx)
+	return l, err
+}
+
+func (c *ConcreteSigar) GetNetTcpConnListContext(ctx context.Context) (NetTcpConnList, error) {
+	l := NetTcpConnList{}
+	err := l.GetContext(ctx)
+	return l, err
+}
+
+func (c *ConcreteSigar) GetNetUdpConnListContext(ctx context.Context) (NetUdpConnList, error) {
+	l := NetUdpConnList{}
+	err := l.GetContext(ctx)
+	return l, err
+}
+
+func (c *ConcreteSigar) GetNetRawConnListContext(ctx context.Context) (NetRawConnList, error) {
+	l := NetRawConnList{}
+	err := l.GetContext(ctx)
+	return l, err
+}
+
+func (c *ConcreteSigar) GetNetTcpV6ConnListContext(ctx context.Context) (NetTcpV6ConnList, error) {
+	l := NetTcpV6ConnList{}
+	err := l.GetContext(ctx)
+	return l, err
+}
+
+func (c *ConcreteSigar) GetNetUdpV6ConnListContext(ctx context.Context) (NetUdpV6ConnList, error) {
+	l := NetUdpV6ConnList{}
+	err := l.GetContext(ctx)
+	return l, err
+}
+
+func (c *ConcreteSigar) GetNetRawV6ConnListContext(ctx context.Context) (NetRawV6ConnList, error) {
+	l := NetRawV6ConnList{}
+	err := l.GetContext(ctx)
+	return l, err
+}
+
+func (c *ConcreteSigar) GetNetConnListContext(ctx context.Context) (NetConnList, error) {
+	l := NetConnList{}
+	err := l.GetContext(ctx)
+	return l, err
+}
+
+func (c *ConcreteSigar) GetDeletedOpenFilesContext(ctx context.Context) (DeletedOpenFiles, error) {
+	d := DeletedOpenFiles{}
+	err := d.GetContext(ctx)
+	return d, err
+}
@@ -0,0 +1,121 @@
+package sigar
+
+import (
+	"context"
+)
+
+// Context-aware variants of the collectors whose reads can block indefinitely.
+// When the deadline of ctx passes they return a *TimeoutError naming the file
+// or pid that was stuck; lists keep the entries gathered up to that point.
+
+func (self *FileSystemList) GetContext(ctx context.Context) error {
+	return self.getContext(ctx, defaultSigar)
+}
+
+func (self *ProcessList) GetContext(ctx context.Context) error {
+	return self.getContext(ctx, defaultSigar)
+}
+
+func (self *NetTcpConnList) GetContext(ctx context.Context) error {
+	return self.getContext(ctx, defaultSigar)
+}
+
+func (self *NetUdpConnList) GetContext(ctx context.Context) error {
+	return self.getContext(ctx, defaultSigar)
+}
+
+func (self *NetRawConnList) GetContext(ctx context.Context) error {
+	return self.getContext(ctx, defaultSigar)
+}
+
+func (self *NetTcpV6ConnList) GetContext(ctx context.Context) error {
+	return self.getContext(ctx, defaultSigar)
+}
+
+func (self *NetUdpV6ConnList) GetContext(ctx context.Context) error {
+	return self.getContext(ctx, defaultSigar)
+}
+
+func (self *NetRawV6ConnList) GetContext(ctx context.Context) error {
+	return self.getContext(ctx, defaultSigar)
+}
+
+func (self *NetConnList) GetContext(ctx context.Context) error {
+	return self.getContext(ctx, defaultSigar)
+}
+
+func (self *DeletedOpenFiles) GetContext(ctx context.Context) error {
+	return self.getContext(ctx, defaultSigar)
+}
+
+func (s *LinuxSigar) GetFileSystemUsageContext(ctx context.Context, path string) (FileSystemUsage, error) {
+	f := FileSystemUsage{}
+	err := f.GetContext(ctx, path)
+	return f, err
+}
+
+func (s *LinuxSigar) GetDirUsageContext(ctx context.Context, path string, options DirUsageOptions) (DirUsage, error) {
+	d := DirUsage{}
+	err := d.GetContext(ctx, path, options)
+	return d, err
+}
+
+func (s *LinuxSigar) GetFileSystemListContext(ctx context.Context) (FileSystemList, error) {
+	l := FileSystemList{}
+	err := l.getContext(ctx, s)
+	return l, err
+}
+
+func (s *LinuxSigar) GetProcessListContext(ctx context.Context) (ProcessList, error) {
+	l := ProcessList{}
+	err := l.getContext(ctx, s)
+	return l, err
+}
+
+func (s *LinuxSigar) GetNetTcpConnListContext(ctx context.Context) (NetTcpConnList, error) {
+	l := NetTcpConnList{}
+	err := l.getContext(ctx, s)
+	return l, err
+}
+
+func (s *LinuxSigar) GetNetUdpConnListContext(ctx context.Context) (NetUdpConnList, error) {
+	l := NetUdpConnList{}
+	err := l.getContext(ctx, s)
+	return l, err
+}
+
+func (s *LinuxSigar) GetNetRawConnListContext(ctx context.Context) (NetRawConnList, error) {
+	l := NetRawConnList{}
+	err := l.getContext(ctx, s)
+	return l, err
+}
+
+func (s *LinuxSigar) GetNetTcpV6ConnListContext(ctx context.Context) (NetTcpV6ConnList, error) {
+	l := NetTcpV6ConnList{}
+	err := l.getContext(ctx, s)
+	return l, err
+}
+
+func (s *LinuxSigar) GetNetUdpV6ConnListContext(ctx context.Context) (NetUdpV6ConnList, error) {
+	l := NetUdpV6ConnList{}
+	err := l.getContext(ctx, s)
+	return l, err
+}
+
+func (s *LinuxSigar) GetNetRawV6ConnListContext(ctx context.Context) (NetRawV6ConnList, error) {
+	l := NetRawV6ConnList{}
+	err := l.getContext(ctx, s)
+	return l, err
+}
+
+func (s *LinuxSigar) GetNetConnListContext(ctx context.Context) (NetConnList, error) {
+	l := NetConnList{}
+	err := l.getContext(ctx, s)
+	return l, err
+}
+
+func (s *LinuxSigar) GetDeletedOpenFilesContext(ctx context.Context) (DeletedOpenFiles, error) {
+	d := DeletedOpenFiles{}
+	err := d.getContext(ctx, s)
+	return d, err
+}
@@ -0,0 +1,140 @@
+// +build !linux
+
+package sigar
+
+import (
+	"context"
+)
+
+// Context-aware variants of the collectors whose reads can block indefinitely.
+// Outside Linux the whole collection is abandoned when the deadline of ctx passes.
+
+func (self *FileSystemList) GetContext(ctx context.Context) error {
+	list, err := blockingCalls.do(ctx, "collect", "FileSystemList", 0, func() (interface{}, error) {
+		list := FileSystemList{}
+		err := list.Get()
+		return list, err
+	})
+	if err != nil {
+		return err
+	}
+	*self = list.(FileSystemList)
+	return nil
+}
+
+func (self *ProcessList) GetContext(ctx context.Context) error {
+	list, err := blockingCalls.do(ctx, "collect", "ProcessList", 0, func() (interface{}, error) {
+		list := ProcessList{}
+		err := list.Get()
+		return list, err
+	})
+	if err != nil {
+		return err
+	}
+	*self = list.(ProcessList)
+	return nil
+}
+
+func (self *NetTcpConnList) GetContext(ctx context.Context) error {
+	list, err := blockingCalls.do(ctx, "collect", "NetTcpConnList", 0, func() (interface{}, error) {
+		list := NetTcpConnList{}
+		err := list.Get()
+		return list, err
+	})
+	if err != nil {
+		return err
+	}
+	*self = list.(NetTcpConnList)
+	return nil
+}
+
+func (self *NetUdpConnList) GetContext(ctx context.Context) error {
+	list, err := blockingCalls.do(ctx, "collect", "NetUdpConnList", 0, func() (interface{}, error) {
+		list := NetUdpConnList{}
+		err := list.Get()
+		return list, err
+	})
+	if err != nil {
+		return err
+	}
+	*self = list.(NetUdpConnList)
+	return nil
+}
+
+func (self *NetRawConnList) GetContext(ctx context.Context) error {
+	list, err := blockingCalls.do(ctx, "collect", "NetRawConnList", 0, func() (interface{}, error) {
+		list := NetRawConnList{}
+		err := list.Get()
+		return list, err
+	})
+	if err != nil {
+		return err
+	}
+	*self = list.(NetRawConnList)
+	return nil
+}
+
+func (self *NetTcpV6ConnList) GetContext(ctx context.Context) error {
+	list, err := blockingCalls.do(ctx, "collect", "NetTcpV6ConnList", 0, func() (interface{}, error) {
+		list := NetTcpV6ConnList{}
+		err := list.Get()
+		return list, err
+	})
+	if err != nil {
+		return err
+	}
+	*self = list.(NetTcpV6ConnList)
+	return nil
+}
+
+func (self *NetUdpV6ConnList) GetContext(ctx context.Context) error {
+	list, err := blockingCalls.do(ctx, "collect", "NetUdpV6ConnList", 0, func() (interface{}, error) {
+		list := NetUdpV6ConnList{}
+		err := list.Get()
+		return list, err
+	})
+	if err != nil {
+		return err
+	}
+	*self = list.(NetUdpV6ConnList)
+	return nil
+}
+
+func (self *NetRawV6ConnList) GetContext(ctx context.Context) error {
+	list, err := blockingCalls.do(ctx, "collect", "NetRawV6ConnList", 0, func() (interface{}, error) {
+		list := NetRawV6ConnList{}
+		err := list.Get()
+		return list, err
+	})
+	if err != nil {
+		return err
+	}
+	*self = list.(NetRawV6ConnList)
+	return nil
+}
+
+func (self *NetConnList) GetContext(ctx context.Context) error {
+	list, err := blockingCalls.do(ctx, "collect", "NetConnList", 0, func() (interface{}, error) {
+		list := NetConnList{}
+		err := list.Get()
+		return list, err
+	})
+	if err != nil {
+		return err
+	}
+	*self = list.(NetConnList)
+	return nil
+}
+
+func (self *DeletedOpenFiles) GetContext(ctx context.Context) error {
+	list, err := blockingCalls.do(ctx, "collect", "DeletedOpenFiles", 0, func() (interface{}, error) {
+		list := DeletedOpenFiles{}
+		err := list.Get()
+		return list, err
+	})
+	if err != nil {
+		return err
+	}
+	*self = list.(DeletedOpenFiles)
+	return nil
+}
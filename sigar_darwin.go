@@ -157,6 +157,18 @@ func (self *NetIfaceList) Get() error {
 	return notImplemented()
 }
 
+// Sizes in statfs are in fundamental blocks
+func statfsFragmentSize(stat *syscall.Statfs_t) uint64 {
+	return uint64(stat.Bsize)
+}
+
+func setStatfsFlags(usage *FileSystemUsage, stat *syscall.Statfs_t) {
+	usage.BlockSize = uint64(stat.Iosize)
+	usage.ReadOnly = stat.Flags&C.MNT_RDONLY != 0
+	usage.NoExec = stat.Flags&C.MNT_NOEXEC != 0
+	usage.NoSuid = stat.Flags&C.MNT_NOSUID != 0
+}
+
 func (self *FileSystemList) Get() error {
 	num, err := getfsstat(nil, C.MNT_NOWAIT)
 	if num < 0 {
@@ -187,6 +199,10 @@ func (self *FileSystemList) Get() error {
 	return err
 }
 
+func (self *FileSystemList) GetPid(pid int) error {
+	return notImplemented()
+}
+
 func (self *DiskList) Get() error {
 	return notImplemented()
 }
@@ -215,6 +231,10 @@ func (self *NetRawV6ConnList) Get() error {
 	return notImplemented()
 }
 
+func (self *NetConnList) Get() error {
+	return notImplemented()
+}
+
 func (self *ProcessList) Get() error {
 	return notImplemented()
 }
@@ -255,6 +275,10 @@ func (self *ProcState) Get(pid int) error {
 	return notImplemented()
 }
 
+func (self *ProcStatus) Get(pid int) error {
+	return notImplemented()
+}
+
 func (self *ProcIo) Get(pid int) error {
 	return notImplemented()
 }
@@ -481,6 +505,70 @@ func (self *SystemDistribution) Get() error {
 	return nil
 }
 
+func (self *KernelStat) Get() error {
+	return notImplemented()
+}
+
+func (self *Pressure) Get() error {
+	return notImplemented()
+}
+
+func (self *Pressure) GetCgroup(path string) error {
+	return notImplemented()
+}
+
+func (self *MemInfo) Get() error {
+	return notImplemented()
+}
+
+func (self *VmStat) Get() error {
+	return notImplemented()
+}
+
+func (self *SwapList) Get() error {
+	return notImplemented()
+}
+
+func (self *NumaNodeList) Get() error {
+	return notImplemented()
+}
+
+func (self *CpuInfo) Get() error {
+	return notImplemented()
+}
+
+func (self *BlockDeviceList) Get() error {
+	return notImplemented()
+}
+
+func (self *MdArrayList) Get() error {
+	return notImplemented()
+}
+
+func (self *DeletedOpenFiles) Get() error {
+	return notImplemented()
+}
+
+func (self *SystemFd) Get() error {
+	return notImplemented()
+}
+
+func (self *ProcFdUsageList) Get() error {
+	return notImplemented()
+}
+
 func notImplemented() error {
 	return ErrNotImplemented
 }
+
+func (self *ProcFd) Get(pid int) error {
+	return notImplemented()
+}
+
+func (self *ProcLimits) Get(pid int) error {
+	return notImplemented()
+}
+
+func (self *ProcFdList) Get(pid int) error {
+	return notImplemented()
+}
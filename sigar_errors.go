@@ -0,0 +1,158 @@
+package sigar
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"syscall"
+)
+
+var ErrNotImplemented error = errors.New("Collection not implemented for this operating system")
+
+// Kinds of collection failure, reported through *Error.
+var (
+	ErrProcessGone      = errors.New("Process no longer exists")
+	ErrPermissionDenied = errors.New("Permission denied")
+	ErrParse            = errors.New("Unable to parse")
+	ErrNotSupported     = errors.New("Not supported by the running kernel")
+)
+
+// Error describes a failure to read or parse one source of a collector.
+//
+// Linux collectors used to return a bare syscall.ESRCH for exited processes
+// and the *os.PathError of other failed reads. Those are now the Err of an
+// *Error, so comparisons like err == syscall.ESRCH or os.IsPermission(err)
+// no longer match. Use IsProcessGone and the other Is functions, or Cause.
+type Error struct {
+	Kind  error  // ErrProcessGone, ErrPermissionDenied, ErrParse, ErrNotImplemented or ErrNotSupported
+	Path  string // The file that was being read
+	Field string // The field that was being parsed, if any
+	Err   error  // The underlying error, if any
+}
+
+func (self *Error) Error() string {
+	str := self.Kind.Error() + ": " + self.Path
+	if self.Field != "" {
+		str += " (" + self.Field + ")"
+	}
+	if self.Err != nil {
+		str += ": " + self.Err.Error()
+	}
+	return str
+}
+
+// Is reports whether target is the kind of this error, for errors.Is.
+func (self *Error) Is(target error) bool {
+	return self.Kind == target
+}
+
+func (self *Error) Unwrap() error {
+	return self.Err
+}
+
+// PartialError is returned by list collectors when some entries could not be
+// collected. The list still holds every entry that was.
+type PartialError struct {
+	Failed map[string]error // Why each pid or device failed, keyed by pid or device name
+}
+
+func (self *PartialError) Error() string {
+	keys := make([]string, 0, len(self.Failed))
+	for key := range self.Failed {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	failures := make([]string, 0, len(keys))
+	for _, key := range keys {
+		failures = append(failures, fmt.Sprintf("%s: %v", key, self.Failed[key]))
+	}
+	return fmt.Sprintf("Failed to collect %d entries: %s", len(keys), strings.Join(failures, "; "))
+}
+
+// Record why an entry failed
+func (self *PartialError) add(key string, err error) {
+	if self.Failed == nil {
+		self.Failed = make(map[string]error)
+	}
+	self.Failed[key] = err
+}
+
+// Return the PartialError, or nil if nothing failed
+func (self *PartialError) orNil() error {
+	if len(self.Failed) == 0 {
+		return nil
+	}
+	return self
+}
+
+// Cause returns the underlying error of an *Error, e.g. syscall.ESRCH for a
+// process that is gone, and any other error unchanged. It gives the errors
+// that collectors returned before *Error to callers without errors.Is.
+func Cause(err error) error {
+	if e, ok := err.(*Error); ok && e.Err != nil {
+		return e.Err
+	}
+	return err
+}
+
+func IsProcessGone(err error) bool {
+	return isKind(err, ErrProcessGone)
+}
+
+func IsPermissionDenied(err error) bool {
+	return isKind(err, ErrPermissionDenied)
+}
+
+func IsParse(err error) bool {
+	return isKind(err, ErrParse)
+}
+
+func IsNotImplemented(err error) bool {
+	return isKind(err, ErrNotImplemented)
+}
+
+func IsNotSupported(err error) bool {
+	return isKind(err, ErrNotSupported)
+}
+
+func IsPartial(err error) bool {
+	_, ok := err.(*PartialError)
+	return ok
+}
+
+func isKind(err error, kind error) bool {
+	if err == kind {
+		return true
+	}
+	if e, ok := err.(*Error); ok {
+		return e.Kind == kind
+	}
+	return false
+}
+
+// Whether err is errno, or an *os.PathError caused by it
+func isErrno(err error, errno syscall.Errno) bool {
+	if pathErr, ok := err.(*os.PathError); ok {
+		err = pathErr.Err
+	}
+	return err == errno
+}
+
+func parseError(path, field string, err error) error {
+	return &Error{Kind: ErrParse, Path: path, Field: field, Err: err}
+}
+
+// Classify an error from reading path. Missing files are reported as-is
+// unless they belong to a process, when the process is gone.
+func readError(path string, err error, isProcFile bool) error {
+	if os.IsPermission(err) {
+		return &Error{Kind: ErrPermissionDenied, Path: path, Err: err}
+	}
+	if isProcFile && (os.IsNotExist(err) || err == syscall.ESRCH) {
+		return &Error{Kind: ErrProcessGone, Path: path, Err: syscall.ESRCH}
+	}
+	return err
+}
@@ -59,6 +59,22 @@ func FormatPercent(percent float64) string {
 	return strconv.FormatFloat(percent, 'f', -1, 64) + "%"
 }
 
+// Percentage of inodes in use, for filesystems with a fixed number of them
+func (self *FileSystemUsage) FilesUsePercent() float64 {
+	if self.Files == 0 {
+		return 0.0
+	}
+	return float64(self.UsedFiles()) * 100 / float64(self.Files)
+}
+
+// Percentage of the soft open files limit in use, 0 when it is unlimited
+func (self *ProcFd) UsePercent() float64 {
+	if self.SoftLimit == 0 || self.SoftLimit == RlimInfinity {
+		return 0.0
+	}
+	return float64(self.Open) * 100 / float64(self.SoftLimit)
+}
+
 func (self *FileSystemUsage) UsePercent() float64 {
 	b_used := (self.Total - self.Free) / 1024
 	b_avail := self.Avail / 1024
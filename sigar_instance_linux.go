@@ -0,0 +1,309 @@
+package sigar
+
+import (
+	"context"
+	"time"
+)
+
+// Accessors mirroring ConcreteSigar, reading from the roots of this instance.
+
+func (s *LinuxSigar) CollectCpuStats(collectionInterval time.Duration) (<-chan Cpu, chan<- struct{}) {
+	return collectCpuStats(collectionInterval, func(cpu *Cpu) error {
+		return cpu.get(s)
+	})
+}
+
+func (s *LinuxSigar) CollectCpuListPercent(collectionInterval time.Duration) (<-chan CpuListPercent, chan<- struct{}) {
+	return collectCpuListPercent(collectionInterval, func(cpuList *CpuList) error {
+		return cpuList.get(s)
+	})
+}
+
+func (s *LinuxSigar) GetLoadAverage() (LoadAverage, error) {
+	l := LoadAverage{}
+	err := l.get(s)
+	return l, err
+}
+
+func (s *LinuxSigar) GetUptime() (Uptime, error) {
+	u := Uptime{}
+	err := u.Get()
+	return u, err
+}
+
+func (s *LinuxSigar) GetMem() (Mem, error) {
+	m := Mem{}
+	err := m.get(s)
+	return m, err
+}
+
+func (s *LinuxSigar) GetSwap() (Swap, error) {
+	sw := Swap{}
+	err := sw.get(s)
+	return sw, err
+}
+
+func (s *LinuxSigar) GetCpu() (Cpu, error) {
+	c := Cpu{}
+	err := c.get(s)
+	return c, err
+}
+
+func (s *LinuxSigar) GetCpuList() (CpuList, error) {
+	c := CpuList{}
+	err := c.get(s)
+	return c, err
+}
+
+func (s *LinuxSigar) GetFileSystemList() (FileSystemList, error) {
+	f := FileSystemList{}
+	err := f.get(s)
+	return f, err
+}
+
+// Mounts in the mount namespace of a process
+func (s *LinuxSigar) GetProcFileSystemList(pid int) (FileSystemList, error) {
+	f := FileSystemList{}
+	err := f.getPid(s, pid)
+	return f, err
+}
+
+func (s *LinuxSigar) GetFileSystemUsage(path string) (FileSystemUsage, error) {
+	f := FileSystemUsage{}
+	err := f.Get(path)
+	return f, err
+}
+
+func (s *LinuxSigar) GetDirUsage(path string, options DirUsageOptions) (DirUsage, error) {
+	d := DirUsage{}
+	err := d.GetContext(context.Background(), path, options)
+	return d, err
+}
+
+func (s *LinuxSigar) GetDiskList() (DiskList, error) {
+	d := DiskList{}
+	err := d.get(s)
+	return d, err
+}
+
+func (s *LinuxSigar) GetNetProtoV4Stats() (NetProtoV4Stats, error) {
+	n := NetProtoV4Stats{}
+	err := n.get(s)
+	return n, err
+}
+
+func (s *LinuxSigar) GetNetProtoV6Stats() (NetProtoV6Stats, error) {
+	n := NetProtoV6Stats{}
+	err := n.get(s)
+	return n, err
+}
+
+func (s *LinuxSigar) GetNetIfaceList() (NetIfaceList, error) {
+	n := NetIfaceList{}
+	err := n.get(s)
+	return n, err
+}
+
+func (s *LinuxSigar) GetNetTcpConnList() (NetTcpConnList, error) {
+	n := NetTcpConnList{}
+	err := n.get(s)
+	return n, err
+}
+
+func (s *LinuxSigar) GetNetUdpConnList() (NetUdpConnList, error) {
+	n := NetUdpConnList{}
+	err := n.get(s)
+	return n, err
+}
+
+func (s *LinuxSigar) GetNetRawConnList() (NetRawConnList, error) {
+	n := NetRawConnList{}
+	err := n.get(s)
+	return n, err
+}
+
+func (s *LinuxSigar) GetNetTcpV6ConnList() (NetTcpV6ConnList, error) {
+	n := NetTcpV6ConnList{}
+	err := n.get(s)
+	return n, err
+}
+
+func (s *LinuxSigar) GetNetUdpV6ConnList() (NetUdpV6ConnList, error) {
+	n := NetUdpV6ConnList{}
+	err := n.get(s)
+	return n, err
+}
+
+func (s *LinuxSigar) GetNetRawV6ConnList() (NetRawV6ConnList, error) {
+	n := NetRawV6ConnList{}
+	err := n.get(s)
+	return n, err
+}
+
+func (s *LinuxSigar) GetNetConnList() (NetConnList, error) {
+	n := NetConnList{}
+	err := n.get(s)
+	return n, err
+}
+
+func (s *LinuxSigar) GetProcessList() (ProcessList, error) {
+	p := ProcessList{}
+	err := p.get(s)
+	return p, err
+}
+
+func (s *LinuxSigar) GetProcList() (ProcList, error) {
+	p := ProcList{}
+	err := p.get(s)
+	return p, err
+}
+
+func (s *LinuxSigar) GetProcState(pid int) (ProcState, error) {
+	p := ProcState{}
+	err := p.get(s, pid)
+	return p, err
+}
+
+func (s *LinuxSigar) GetProcStatus(pid int) (ProcStatus, error) {
+	p := ProcStatus{}
+	err := p.get(s, pid)
+	return p, err
+}
+
+func (s *LinuxSigar) GetProcIo(pid int) (ProcIo, error) {
+	p := ProcIo{}
+	err := p.get(s, pid)
+	return p, err
+}
+
+func (s *LinuxSigar) GetProcMem(pid int) (ProcMem, error) {
+	p := ProcMem{}
+	err := p.get(s, pid)
+	return p, err
+}
+
+func (s *LinuxSigar) GetProcTime(pid int) (ProcTime, error) {
+	p := ProcTime{}
+	err := p.get(s, pid)
+	return p, err
+}
+
+func (s *LinuxSigar) GetProcArgs(pid int) (ProcArgs, error) {
+	p := ProcArgs{}
+	err := p.get(s, pid)
+	return p, err
+}
+
+func (s *LinuxSigar) GetProcExe(pid int) (ProcExe, error) {
+	p := ProcExe{}
+	err := p.get(s, pid)
+	return p, err
+}
+
+func (s *LinuxSigar) GetProcFd(pid int) (ProcFd, error) {
+	p := ProcFd{}
+	err := p.get(s, pid)
+	return p, err
+}
+
+func (s *LinuxSigar) GetProcLimits(pid int) (ProcLimits, error) {
+	p := ProcLimits{}
+	err := p.get(s, pid)
+	return p, err
+}
+
+func (s *LinuxSigar) GetProcFdUsageList() (ProcFdUsageList, error) {
+	p := ProcFdUsageList{}
+	err := p.get(s)
+	return p, err
+}
+
+func (s *LinuxSigar) GetProcFdList(pid int) (ProcFdList, error) {
+	p := ProcFdList{}
+	err := p.get(s, pid)
+	return p, err
+}
+
+func (s *LinuxSigar) GetSystemInfo() (SystemInfo, error) {
+	i := SystemInfo{}
+	err := i.Get()
+	return i, err
+}
+
+func (s *LinuxSigar) GetSystemDistribution() (SystemDistribution, error) {
+	d := SystemDistribution{}
+	err := d.get(s)
+	return d, err
+}
+
+func (s *LinuxSigar) GetKernelStat() (KernelStat, error) {
+	k := KernelStat{}
+	err := k.get(s)
+	return k, err
+}
+
+func (s *LinuxSigar) GetPressure() (Pressure, error) {
+	p := Pressure{}
+	err := p.get(s)
+	return p, err
+}
+
+func (s *LinuxSigar) GetCgroupPressure(path string) (Pressure, error) {
+	p := Pressure{}
+	err := p.getCgroup(s, path)
+	return p, err
+}
+
+func (s *LinuxSigar) GetMemInfo() (MemInfo, error) {
+	m := MemInfo{}
+	err := m.get(s)
+	return m, err
+}
+
+func (s *LinuxSigar) GetVmStat() (VmStat, error) {
+	v := VmStat{}
+	err := v.get(s)
+	return v, err
+}
+
+func (s *LinuxSigar) GetSwapList() (SwapList, error) {
+	l := SwapList{}
+	err := l.get(s)
+	return l, err
+}
+
+func (s *LinuxSigar) GetNumaNodeList() (NumaNodeList, error) {
+	n := NumaNodeList{}
+	err := n.get(s)
+	return n, err
+}
+
+func (s *LinuxSigar) GetCpuInfo() (CpuInfo, error) {
+	c := CpuInfo{}
+	err := c.get(s)
+	return c, err
+}
+
+func (s *LinuxSigar) GetBlockDeviceList() (BlockDeviceList, error) {
+	b := BlockDeviceList{}
+	err := b.get(s)
+	return b, err
+}
+
+func (s *LinuxSigar) GetMdArrayList() (MdArrayList, error) {
+	m := MdArrayList{}
+	err := m.get(s)
+	return m, err
+}
+
+func (s *LinuxSigar) GetDeletedOpenFiles() (DeletedOpenFiles, error) {
+	d := DeletedOpenFiles{}
+	err := d.get(s)
+	return d, err
+}
+
+func (s *LinuxSigar) GetSystemFd() (SystemFd, error) {
+	x := SystemFd{}
+	err := x.get(s)
+	return x, err
+}
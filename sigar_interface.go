@@ -1,60 +1,154 @@
 package sigar
 
 import (
-	"errors"
 	"fmt"
 	"net"
+	"strings"
 	"time"
 )
 
 type Sigar interface {
 	CollectCpuStats(collectionInterval time.Duration) (<-chan Cpu, chan<- struct{})
+	CollectCpuListPercent(collectionInterval time.Duration) (<-chan CpuListPercent, chan<- struct{})
+	GetCpu() (Cpu, error)
+	GetCpuList() (CpuList, error)
 	GetLoadAverage() (LoadAverage, error)
+	GetUptime() (Uptime, error)
 	GetMem() (Mem, error)
 	GetSwap() (Swap, error)
+	GetFileSystemList() (FileSystemList, error)
+	GetProcFileSystemList(pid int) (FileSystemList, error)
 	GetFileSystemUsage(string) (FileSystemUsage, error)
+	GetDirUsage(path string, options DirUsageOptions) (DirUsage, error)
+	GetDiskList() (DiskList, error)
+	GetNetProtoV4Stats() (NetProtoV4Stats, error)
+	GetNetProtoV6Stats() (NetProtoV6Stats, error)
+	GetNetIfaceList() (NetIfaceList, error)
+	GetNetTcpConnList() (NetTcpConnList, error)
+	GetNetUdpConnList() (NetUdpConnList, error)
+	GetNetRawConnList() (NetRawConnList, error)
+	GetNetTcpV6ConnList() (NetTcpV6ConnList, error)
+	GetNetUdpV6ConnList() (NetUdpV6ConnList, error)
+	GetNetRawV6ConnList() (NetRawV6ConnList, error)
+	GetNetConnList() (NetConnList, error)
+	GetProcessList() (ProcessList, error)
+	GetProcList() (ProcList, error)
+	GetProcState(pid int) (ProcState, error)
+	GetProcStatus(pid int) (ProcStatus, error)
+	GetProcIo(pid int) (ProcIo, error)
+	GetProcMem(pid int) (ProcMem, error)
+	GetProcTime(pid int) (ProcTime, error)
+	GetProcArgs(pid int) (ProcArgs, error)
+	GetProcExe(pid int) (ProcExe, error)
+	GetProcFdList(pid int) (ProcFdList, error)
+	GetProcFd(pid int) (ProcFd, error)
+	GetProcLimits(pid int) (ProcLimits, error)
+	GetProcFdUsageList() (ProcFdUsageList, error)
 	GetSystemInfo() (SystemInfo, error)
 	GetSystemDistribution() (SystemDistribution, error)
+	GetSystemFd() (SystemFd, error)
+	GetDeletedOpenFiles() (DeletedOpenFiles, error)
+	GetMdArrayList() (MdArrayList, error)
+	GetBlockDeviceList() (BlockDeviceList, error)
+	GetCpuInfo() (CpuInfo, error)
+	GetNumaNodeList() (NumaNodeList, error)
+	GetSwapList() (SwapList, error)
+	GetVmStat() (VmStat, error)
+	GetMemInfo() (MemInfo, error)
+	GetPressure() (Pressure, error)
+	GetKernelStat() (KernelStat, error)
 }
 
-var ErrNotImplemented error = errors.New("Collection not implemented for this operating system")
-
 // Simple Get() that returns an error
 type Getter interface {
 	Get() error
 }
 
 type Cpu struct {
-	User    uint64
-	Nice    uint64
-	Sys     uint64
-	Idle    uint64
-	Wait    uint64
-	Irq     uint64
-	SoftIrq uint64
-	Stolen  uint64
-	Guest   uint64
+	User      uint64
+	Nice      uint64
+	Sys       uint64
+	Idle      uint64
+	Wait      uint64
+	Irq       uint64
+	SoftIrq   uint64
+	Stolen    uint64
+	Guest     uint64
+	GuestNice uint64
 }
 
 func (cpu *Cpu) Total() uint64 {
 	return cpu.User + cpu.Nice + cpu.Sys + cpu.Idle +
-		cpu.Wait + cpu.Irq + cpu.SoftIrq + cpu.Stolen + cpu.Guest
+		cpu.Wait + cpu.Irq + cpu.SoftIrq + cpu.Stolen + cpu.Guest + cpu.GuestNice
 }
 
 func (cpu Cpu) Delta(other Cpu) Cpu {
 	return Cpu{
-		User:    cpu.User - other.User,
-		Nice:    cpu.Nice - other.Nice,
-		Sys:     cpu.Sys - other.Sys,
-		Idle:    cpu.Idle - other.Idle,
-		Wait:    cpu.Wait - other.Wait,
-		Irq:     cpu.Irq - other.Irq,
-		SoftIrq: cpu.SoftIrq - other.SoftIrq,
-		Stolen:  cpu.Stolen - other.Stolen,
-		Guest:   cpu.Guest - other.Guest,
+		User:      counterDelta(cpu.User, other.User),
+		Nice:      counterDelta(cpu.Nice, other.Nice),
+		Sys:       counterDelta(cpu.Sys, other.Sys),
+		Idle:      counterDelta(cpu.Idle, other.Idle),
+		Wait:      counterDelta(cpu.Wait, other.Wait),
+		Irq:       counterDelta(cpu.Irq, other.Irq),
+		SoftIrq:   counterDelta(cpu.SoftIrq, other.SoftIrq),
+		Stolen:    counterDelta(cpu.Stolen, other.Stolen),
+		Guest:     counterDelta(cpu.Guest, other.Guest),
+		GuestNice: counterDelta(cpu.GuestNice, other.GuestNice),
+	}
+}
+
+// Percent returns the fraction of time spent in each state, usually of a
+// Delta. Guest time is already counted in User and Nice, so it isn't
+// counted twice.
+func (cpu Cpu) Percent() CpuPercent {
+	total := cpu.Total() - cpu.Guest - cpu.GuestNice
+	if total == 0 {
+		return CpuPercent{}
+	}
+
+	fraction := func(ticks uint64) float64 {
+		return float64(ticks) / float64(total)
+	}
+	return CpuPercent{
+		User:      fraction(cpu.User),
+		Nice:      fraction(cpu.Nice),
+		Sys:       fraction(cpu.Sys),
+		Idle:      fraction(cpu.Idle),
+		Wait:      fraction(cpu.Wait),
+		Irq:       fraction(cpu.Irq),
+		SoftIrq:   fraction(cpu.SoftIrq),
+		Stolen:    fraction(cpu.Stolen),
+		Guest:     fraction(cpu.Guest),
+		GuestNice: fraction(cpu.GuestNice),
 	}
 }
 
+// Fractions of time, from 0 to 1, that a CPU spent in each state
+type CpuPercent struct {
+	User      float64
+	Nice      float64
+	Sys       float64
+	Idle      float64
+	Wait      float64
+	Irq       float64
+	SoftIrq   float64
+	Stolen    float64
+	Guest     float64 // Included in User
+	GuestNice float64 // Included in Nice
+}
+
+// Fraction of time the CPU was doing work, i.e. not idle, waiting on I/O or
+// stolen by the hypervisor. Zero for an offline CPU.
+func (self CpuPercent) Busy() float64 {
+	return self.User + self.Nice + self.Sys + self.Irq + self.SoftIrq
+}
+
+// Per-core and aggregate CPU utilization
+type CpuListPercent struct {
+	Total CpuPercent   // All cores together
+	List  []CpuPercent // Indexed like CpuList.List
+}
+
 type LoadAverage struct {
 	One, Five, Fifteen float64
 }
@@ -63,6 +157,105 @@ type Uptime struct {
 	Length float64
 }
 
+// Pressure stall information: how much time tasks spent waiting on CPU,
+// memory and I/O
+type Pressure struct {
+	Cpu    PressureResource
+	Memory PressureResource
+	Io     PressureResource
+}
+
+type PressureResource struct {
+	Some PressureStat // At least one task was stalled
+	Full PressureStat // All non-idle tasks were stalled at once
+}
+
+type PressureStat struct {
+	Avg10  float64 // Percentage of time stalled over the last 10 seconds
+	Avg60  float64
+	Avg300 float64
+	Total  uint64 // Microseconds stalled since boot
+}
+
+// Delta of the Total of each stat. The averages keep their current values.
+func (self Pressure) Delta(other Pressure) Pressure {
+	return Pressure{
+		Cpu:    self.Cpu.Delta(other.Cpu),
+		Memory: self.Memory.Delta(other.Memory),
+		Io:     self.Io.Delta(other.Io),
+	}
+}
+
+func (self PressureResource) Delta(other PressureResource) PressureResource {
+	return PressureResource{
+		Some: self.Some.Delta(other.Some),
+		Full: self.Full.Delta(other.Full),
+	}
+}
+
+func (self PressureStat) Delta(other PressureStat) PressureStat {
+	delta := self
+	delta.Total = counterDelta(self.Total, other.Total)
+	return delta
+}
+
+// Stalled returns the fraction of interval spent stalled, for a Delta taken
+// over interval
+func (self PressureStat) Stalled(interval time.Duration) float64 {
+	micros := interval.Nanoseconds() / 1000
+	if micros <= 0 {
+		return 0
+	}
+	return float64(self.Total) / float64(micros)
+}
+
+// System-wide kernel activity counters, from /proc/stat on Linux
+type KernelStat struct {
+	ContextSwitches uint64 // Since boot
+	Interrupts      uint64 // Since boot, of all interrupts
+	SoftIrqs        uint64 // Since boot, of all softirqs
+	Forks           uint64 // Processes and threads created since boot
+	ProcsRunning    uint64 // Currently runnable
+	ProcsBlocked    uint64 // Currently blocked waiting for I/O
+	BootTime        uint64 // Seconds since epoch
+}
+
+// Delta of the counters. ProcsRunning, ProcsBlocked and BootTime keep their
+// current values.
+func (self KernelStat) Delta(other KernelStat) KernelStat {
+	return KernelStat{
+		ContextSwitches: counterDelta(self.ContextSwitches, other.ContextSwitches),
+		Interrupts:      counterDelta(self.Interrupts, other.Interrupts),
+		SoftIrqs:        counterDelta(self.SoftIrqs, other.SoftIrqs),
+		Forks:           counterDelta(self.Forks, other.Forks),
+		ProcsRunning:    self.ProcsRunning,
+		ProcsBlocked:    self.ProcsBlocked,
+		BootTime:        self.BootTime,
+	}
+}
+
+// PerSecond converts a Delta taken over interval into rates
+func (self KernelStat) PerSecond(interval time.Duration) KernelStatRate {
+	seconds := interval.Seconds()
+	if seconds <= 0 {
+		return KernelStatRate{}
+	}
+	return KernelStatRate{
+		ContextSwitches: float64(self.ContextSwitches) / seconds,
+		Interrupts:      float64(self.Interrupts) / seconds,
+		SoftIrqs:        float64(self.SoftIrqs) / seconds,
+		Forks:           float64(self.Forks) / seconds,
+	}
+}
+
+// Per second rates of the KernelStat counters
+type KernelStatRate struct {
+	ContextSwitches float64
+	Interrupts      float64
+	SoftIrqs        float64
+	Forks           float64
+}
+
 type Mem struct {
 	Total      uint64
 	Used       uint64
@@ -71,36 +264,416 @@ type Mem struct {
 	ActualUsed uint64
 }
 
+// MemInfo holds every /proc/meminfo value, in bytes unless noted. Keys this
+// version doesn't know about are kept in Other.
+type MemInfo struct {
+	MemTotal          uint64
+	MemFree           uint64
+	MemAvailable      uint64 // Since 3.14
+	HasMemAvailable   bool   // Whether the kernel reported MemAvailable, which may be zero
+	Buffers           uint64
+	Cached            uint64
+	SwapCached        uint64
+	Active            uint64
+	Inactive          uint64
+	ActiveAnon        uint64
+	InactiveAnon      uint64
+	ActiveFile        uint64
+	InactiveFile      uint64
+	Unevictable       uint64
+	Mlocked           uint64
+	SwapTotal         uint64
+	SwapFree          uint64
+	Dirty             uint64
+	Writeback         uint64
+	AnonPages         uint64
+	Mapped            uint64
+	Shmem             uint64
+	KReclaimable      uint64
+	Slab              uint64
+	SReclaimable      uint64
+	SUnreclaim        uint64
+	KernelStack       uint64
+	PageTables        uint64
+	NFSUnstable       uint64
+	Bounce            uint64
+	WritebackTmp      uint64
+	CommitLimit       uint64
+	CommittedAS       uint64
+	VmallocTotal      uint64
+	VmallocUsed       uint64
+	VmallocChunk      uint64
+	Percpu            uint64
+	HardwareCorrupted uint64
+	AnonHugePages     uint64
+	ShmemHugePages    uint64
+	ShmemPmdMapped    uint64
+	FileHugePages     uint64
+	FilePmdMapped     uint64
+	CmaTotal          uint64
+	CmaFree           uint64
+	HugePagesTotal    uint64 // Pages, not bytes
+	HugePagesFree     uint64 // Pages, not bytes
+	HugePagesRsvd     uint64 // Pages, not bytes
+	HugePagesSurp     uint64 // Pages, not bytes
+	Hugepagesize      uint64
+	Hugetlb           uint64
+	DirectMap4k       uint64
+	DirectMap2M       uint64
+	DirectMap1G       uint64
+
+	Other map[string]uint64 // In bytes when the kernel reports kB
+}
+
+// VmStat holds the virtual memory activity counters of /proc/vmstat, since
+// boot. Every other key, including the nr_* gauges, is kept in Other.
+type VmStat struct {
+	PgpgIn                 uint64 // KiB paged in from disk
+	PgpgOut                uint64 // KiB paged out to disk
+	PswpIn                 uint64 // Pages swapped in
+	PswpOut                uint64 // Pages swapped out
+	PgFault                uint64
+	PgMajFault             uint64 // Faults that required disk I/O
+	PgScanKswapd           uint64
+	PgScanDirect           uint64 // Reclaim scans done by allocating tasks
+	PgStealKswapd          uint64
+	PgStealDirect          uint64
+	OomKill                uint64 // Since 4.13
+	ThpFaultAlloc          uint64
+	ThpFaultFallback       uint64
+	ThpCollapseAlloc       uint64
+	ThpCollapseAllocFailed uint64
+	ThpSplitPage           uint64
+
+	Other map[string]uint64
+}
+
+// Delta of the counters. The nr_* gauges in Other keep their current values.
+func (self VmStat) Delta(other VmStat) VmStat {
+	delta := VmStat{
+		PgpgIn:                 counterDelta(self.PgpgIn, other.PgpgIn),
+		PgpgOut:                counterDelta(self.PgpgOut, other.PgpgOut),
+		PswpIn:                 counterDelta(self.PswpIn, other.PswpIn),
+		PswpOut:                counterDelta(self.PswpOut, other.PswpOut),
+		PgFault:                counterDelta(self.PgFault, other.PgFault),
+		PgMajFault:             counterDelta(self.PgMajFault, other.PgMajFault),
+		PgScanKswapd:           counterDelta(self.PgScanKswapd, other.PgScanKswapd),
+		PgScanDirect:           counterDelta(self.PgScanDirect, other.PgScanDirect),
+		PgStealKswapd:          counterDelta(self.PgStealKswapd, other.PgStealKswapd),
+		PgStealDirect:          counterDelta(self.PgStealDirect, other.PgStealDirect),
+		OomKill:                counterDelta(self.OomKill, other.OomKill),
+		ThpFaultAlloc:          counterDelta(self.ThpFaultAlloc, other.ThpFaultAlloc),
+		ThpFaultFallback:       counterDelta(self.ThpFaultFallback, other.ThpFaultFallback),
+		ThpCollapseAlloc:       counterDelta(self.ThpCollapseAlloc, other.ThpCollapseAlloc),
+		ThpCollapseAllocFailed: counterDelta(self.ThpCollapseAllocFailed, other.ThpCollapseAllocFailed),
+		ThpSplitPage:           counterDelta(self.ThpSplitPage, other.ThpSplitPage),
+	}
+
+	if self.Other != nil {
+		delta.Other = make(map[string]uint64, len(self.Other))
+		for key, value := range self.Other {
+			if strings.HasPrefix(key, "nr_") {
+				delta.Other[key] = value
+			} else {
+				delta.Other[key] = counterDelta(value, other.Other[key])
+			}
+		}
+	}
+	return delta
+}
+
+// PerSecond converts a Delta taken over interval into rates
+func (self VmStat) PerSecond(interval time.Duration) VmStatRate {
+	seconds := interval.Seconds()
+	if seconds <= 0 {
+		return VmStatRate{}
+	}
+
+	rate := VmStatRate{
+		PgpgIn:                 float64(self.PgpgIn) / seconds,
+		PgpgOut:                float64(self.PgpgOut) / seconds,
+		PswpIn:                 float64(self.PswpIn) / seconds,
+		PswpOut:                float64(self.PswpOut) / seconds,
+		PgFault:                float64(self.PgFault) / seconds,
+		PgMajFault:             float64(self.PgMajFault) / seconds,
+		PgScanKswapd:           float64(self.PgScanKswapd) / seconds,
+		PgScanDirect:           float64(self.PgScanDirect) / seconds,
+		PgStealKswapd:          float64(self.PgStealKswapd) / seconds,
+		PgStealDirect:          float64(self.PgStealDirect) / seconds,
+		OomKill:                float64(self.OomKill) / seconds,
+		ThpFaultAlloc:          float64(self.ThpFaultAlloc) / seconds,
+		ThpFaultFallback:       float64(self.ThpFaultFallback) / seconds,
+		ThpCollapseAlloc:       float64(self.ThpCollapseAlloc) / seconds,
+		ThpCollapseAllocFailed: float64(self.ThpCollapseAllocFailed) / seconds,
+		ThpSplitPage:           float64(self.ThpSplitPage) / seconds,
+	}
+
+	if self.Other != nil {
+		rate.Other = make(map[string]float64, len(self.Other))
+		for key, value := range self.Other {
+			if !strings.HasPrefix(key, "nr_") {
+				rate.Other[key] = float64(value) / seconds
+			}
+		}
+	}
+	return rate
+}
+
+// Per second rates of the VmStat counters
+type VmStatRate struct {
+	PgpgIn                 float64
+	PgpgOut                float64
+	PswpIn                 float64
+	PswpOut                float64
+	PgFault                float64
+	PgMajFault             float64
+	PgScanKswapd           float64
+	PgScanDirect           float64
+	PgStealKswapd          float64
+	PgStealDirect          float64
+	OomKill                float64
+	ThpFaultAlloc          float64
+	ThpFaultFallback       float64
+	ThpCollapseAlloc       float64
+	ThpCollapseAllocFailed float64
+	ThpSplitPage           float64
+
+	Other map[string]float64 // Counters only
+}
+
 type Swap struct {
 	Total uint64
 	Used  uint64
 	Free  uint64
 }
 
+type NumaNodeList struct {
+	List []NumaNode // In node id order
+}
+
+type NumaNode struct {
+	Id        int
+	Cpus      []int // Ids of the CPUs attached to the node
+	Distances []int // Relative distance to each node, indexed like NumaNodeList.List
+
+	MemTotal uint64 // Bytes
+	MemFree  uint64 // Bytes
+	MemUsed  uint64 // Bytes
+
+	NumaStat NumaStat
+}
+
+// Page allocation counters of a NUMA node, from numastat
+type NumaStat struct {
+	NumaHit       uint64 // Allocated on this node as intended
+	NumaMiss      uint64 // Allocated on this node although another was preferred
+	NumaForeign   uint64 // Intended for this node but allocated on another
+	InterleaveHit uint64 // Interleaved allocations that landed on this node as intended
+	LocalNode     uint64 // Allocated on this node while the process ran on it
+	OtherNode     uint64 // Allocated on this node while the process ran on another
+}
+
+// NodeOfCpu returns the id of the node a CPU is attached to, e.g. of
+// ProcState.Processor, or -1 if it isn't attached to any.
+func (self NumaNodeList) NodeOfCpu(cpu int) int {
+	for _, node := range self.List {
+		for _, id := range node.Cpus {
+			if id == cpu {
+				return node.Id
+			}
+		}
+	}
+	return -1
+}
+
+type SwapList struct {
+	List []SwapDevice
+}
+
+type SwapDevice struct {
+	Name     string // Path of the swap partition or file
+	Type     string // "partition" or "file"
+	Size     uint64 // Bytes
+	Used     uint64 // Bytes
+	Priority int
+
+	IsZram bool     // Compressed RAM, not disk
+	Zram   ZramStat // Only set when IsZram
+}
+
+// Compression stats of a zram device, from /sys/block/zram*/mm_stat
+type ZramStat struct {
+	OrigDataSize   uint64 // Bytes stored, before compression
+	ComprDataSize  uint64 // Bytes stored, after compression
+	MemUsedTotal   uint64 // Bytes of RAM used, including allocator overhead
+	MemLimit       uint64 // Bytes, 0 if unlimited
+	MemUsedMax     uint64 // Bytes
+	SamePages      uint64 // Pages filled with one value, which take no space
+	PagesCompacted uint64
+	HugePages      uint64 // Incompressible pages, since 4.20
+}
+
+// How many times smaller the data is in RAM than it was originally
+func (self ZramStat) CompressionRatio() float64 {
+	if self.MemUsedTotal == 0 {
+		return 0
+	}
+	return float64(self.OrigDataSize) / float64(self.MemUsedTotal)
+}
+
 type CpuList struct {
-	List []Cpu
+	List []Cpu // Indexed by logical CPU id, offline CPUs are zero
+}
+
+// Delta of each core. Cores that were offline in either sample, e.g. that
+// were hot-plugged in between, have a zero delta.
+func (self CpuList) Delta(other CpuList) CpuList {
+	delta := CpuList{List: make([]Cpu, len(self.List))}
+	for i, cpu := range self.List {
+		if i >= len(other.List) || cpu.Total() == 0 || other.List[i].Total() == 0 {
+			continue
+		}
+		delta.List[i] = cpu.Delta(other.List[i])
+	}
+	return delta
+}
+
+// Sum of every core
+func (self CpuList) Total() Cpu {
+	total := Cpu{}
+	for _, cpu := range self.List {
+		total.User += cpu.User
+		total.Nice += cpu.Nice
+		total.Sys += cpu.Sys
+		total.Idle += cpu.Idle
+		total.Wait += cpu.Wait
+		total.Irq += cpu.Irq
+		total.SoftIrq += cpu.SoftIrq
+		total.Stolen += cpu.Stolen
+		total.Guest += cpu.Guest
+		total.GuestNice += cpu.GuestNice
+	}
+	return total
+}
+
+// Percent returns the utilization of each core and of all of them together,
+// usually of a Delta.
+func (self CpuList) Percent() CpuListPercent {
+	percent := CpuListPercent{
+		Total: self.Total().Percent(),
+		List:  make([]CpuPercent, len(self.List)),
+	}
+	for i, cpu := range self.List {
+		percent.List[i] = cpu.Percent()
+	}
+	return percent
+}
+
+// Inventory of the logical CPUs, online or not
+type CpuInfo struct {
+	List []LogicalCpu // In CPU id order
+}
+
+type LogicalCpu struct {
+	Id     int // Logical CPU id, as used by CpuList and ProcState.Processor
+	Online bool
+
+	Vendor    string
+	ModelName string
+	Flags     []string
+
+	// Topology, -1 where unknown, e.g. for an offline CPU
+	SocketId int
+	CoreId   int // Unique within the socket
+	ThreadId int // Index among the hyperthreads of the core
+
+	CurFreq  uint64 // Hz, zero where unknown
+	MinFreq  uint64 // Hz
+	MaxFreq  uint64 // Hz
+	Governor string // Frequency scaling governor, e.g. "powersave"
+}
+
+// Number of online CPUs
+func (self CpuInfo) Online() int {
+	online := 0
+	for _, cpu := range self.List {
+		if cpu.Online {
+			online++
+		}
+	}
+	return online
 }
 
 type FileSystem struct {
 	DirName     string
 	DevName     string
-	TypeName    string
+	TypeName    string // Class of the filesystem, e.g. "local"
 	SysTypeName string
 	Options     string
-	Flags       uint32
+	Flags       uint32 // MS_* flags of the mount options, on Linux
+
+	// From mountinfo on Linux
+	Class        FileSystemClass
+	MountId      int
+	ParentId     int
+	Major        uint64
+	Minor        uint64
+	Root         string // Directory of the filesystem mounted on DirName, e.g. for bind mounts
+	Propagation  string // e.g. "shared:1", empty for private mounts
+	SuperOptions string // Options of the filesystem itself rather than of this mount
+}
+
+type FileSystemClass int
+
+const (
+	FileSystemLocal = FileSystemClass(iota + 1)
+	FileSystemNetwork
+	FileSystemPseudo // No storage of its own, e.g. proc, cgroup or tmpfs
+	FileSystemOverlay
+)
+
+func (self FileSystemClass) String() string {
+	switch self {
+	case FileSystemLocal:
+		return "local"
+	case FileSystemNetwork:
+		return "network"
+	case FileSystemPseudo:
+		return "pseudo"
+	case FileSystemOverlay:
+		return "overlay"
+	}
+	return ""
 }
 
 type FileSystemList struct {
 	List []FileSystem
 }
 
+// FileSystemUsage sizes are in bytes on every platform. Linux and Darwin
+// reported KiB before, see the README.
 type FileSystemUsage struct {
-	Total     uint64
-	Used      uint64
-	Free      uint64
-	Avail     uint64
-	Files     uint64
+	Total     uint64 // Bytes
+	Used      uint64 // Bytes
+	Free      uint64 // Bytes
+	Avail     uint64 // Bytes available to unprivileged users
+	Reserved  uint64 // Bytes free but reserved for root
+	Files     uint64 // Inodes
 	FreeFiles uint64
+
+	BlockSize     uint64 // Preferred I/O size
+	FragmentSize  uint64 // Allocation unit, of which the sizes are multiples
+	MaxNameLength uint64 // Zero where unknown
+	ReadOnly      bool
+	NoExec        bool
+	NoSuid        bool
+}
+
+func (self FileSystemUsage) UsedFiles() uint64 {
+	if self.FreeFiles > self.Files {
+		return 0
+	}
+	return self.Files - self.FreeFiles
 }
 
 type NetProtoV4Stats struct {
@@ -161,6 +734,77 @@ type UDPStats struct {
 	SndbufErrors uint64 // Not reported by snmp6
 }
 
+func (self NetProtoV4Stats) Delta(other NetProtoV4Stats) NetProtoV4Stats {
+	return NetProtoV4Stats{
+		IP:   self.IP.Delta(other.IP),
+		ICMP: self.ICMP.Delta(other.ICMP),
+		TCP:  self.TCP.Delta(other.TCP),
+		UDP:  self.UDP.Delta(other.UDP),
+	}
+}
+
+func (self NetProtoV6Stats) Delta(other NetProtoV6Stats) NetProtoV6Stats {
+	return NetProtoV6Stats{
+		IP:   self.IP.Delta(other.IP),
+		ICMP: self.ICMP.Delta(other.ICMP),
+		TCP:  self.TCP.Delta(other.TCP),
+		UDP:  self.UDP.Delta(other.UDP),
+	}
+}
+
+func (self IPStats) Delta(other IPStats) IPStats {
+	return IPStats{
+		InReceives:      counterDelta(self.InReceives, other.InReceives),
+		InHdrErrors:     counterDelta(self.InHdrErrors, other.InHdrErrors),
+		InAddrErrors:    counterDelta(self.InAddrErrors, other.InAddrErrors),
+		ForwDatagrams:   counterDelta(self.ForwDatagrams, other.ForwDatagrams),
+		InDelivers:      counterDelta(self.InDelivers, other.InDelivers),
+		InDiscards:      counterDelta(self.InDiscards, other.InDiscards),
+		InUnknownProtos: counterDelta(self.InUnknownProtos, other.InUnknownProtos),
+		OutRequests:     counterDelta(self.OutRequests, other.OutRequests),
+		OutDiscards:     counterDelta(self.OutDiscards, other.OutDiscards),
+		OutNoRoutes:     counterDelta(self.OutNoRoutes, other.OutNoRoutes),
+	}
+}
+
+func (self ICMPStats) Delta(other ICMPStats) ICMPStats {
+	return ICMPStats{
+		InMsgs:          counterDelta(self.InMsgs, other.InMsgs),
+		InErrors:        counterDelta(self.InErrors, other.InErrors),
+		InDestUnreachs:  counterDelta(self.InDestUnreachs, other.InDestUnreachs),
+		OutMsgs:         counterDelta(self.OutMsgs, other.OutMsgs),
+		OutErrors:       counterDelta(self.OutErrors, other.OutErrors),
+		OutDestUnreachs: counterDelta(self.OutDestUnreachs, other.OutDestUnreachs),
+	}
+}
+
+// CurrEstab is a gauge, so it keeps its current value
+func (self TCPStats) Delta(other TCPStats) TCPStats {
+	return TCPStats{
+		ActiveOpens:  counterDelta(self.ActiveOpens, other.ActiveOpens),
+		PassiveOpens: counterDelta(self.PassiveOpens, other.PassiveOpens),
+		AttemptFails: counterDelta(self.AttemptFails, other.AttemptFails),
+		EstabResets:  counterDelta(self.EstabResets, other.EstabResets),
+		CurrEstab:    self.CurrEstab,
+		InSegs:       counterDelta(self.InSegs, other.InSegs),
+		OutSegs:      counterDelta(self.OutSegs, other.OutSegs),
+		RetransSegs:  counterDelta(self.RetransSegs, other.RetransSegs),
+		InErrs:       counterDelta(self.InErrs, other.InErrs),
+		OutRsts:      counterDelta(self.OutRsts, other.OutRsts),
+	}
+}
+
+func (self UDPStats) Delta(other UDPStats) UDPStats {
+	return UDPStats{
+		InDatagrams:  counterDelta(self.InDatagrams, other.InDatagrams),
+		OutDatagrams: counterDelta(self.OutDatagrams, other.OutDatagrams),
+		InErrors:     counterDelta(self.InErrors, other.InErrors),
+		NoPorts:      counterDelta(self.NoPorts, other.NoPorts),
+		RcvbufErrors: counterDelta(self.RcvbufErrors, other.RcvbufErrors),
+		SndbufErrors: counterDelta(self.SndbufErrors, other.SndbufErrors),
+	}
+}
+
 type NetIface struct {
 	Name       string
 	MTU        uint64
@@ -187,6 +831,35 @@ type NetIface struct {
 	SendCollisions    uint64
 }
 
+// Delta of the counters. Name, MTU, Mac and LinkStatus keep their current values.
+func (self NetIface) Delta(other NetIface) NetIface {
+	return NetIface{
+		Name:       self.Name,
+		MTU:        self.MTU,
+		Mac:        self.Mac,
+		LinkStatus: self.LinkStatus,
+
+		SendBytes:      counterDelta(self.SendBytes, other.SendBytes),
+		RecvBytes:      counterDelta(self.RecvBytes, other.RecvBytes),
+		SendPackets:    counterDelta(self.SendPackets, other.SendPackets),
+		RecvPackets:    counterDelta(self.RecvPackets, other.RecvPackets),
+		SendCompressed: counterDelta(self.SendCompressed, other.SendCompressed),
+		RecvCompressed: counterDelta(self.RecvCompressed, other.RecvCompressed),
+		RecvMulticast:  counterDelta(self.RecvMulticast, other.RecvMulticast),
+
+		SendErrors:     counterDelta(self.SendErrors, other.SendErrors),
+		RecvErrors:     counterDelta(self.RecvErrors, other.RecvErrors),
+		SendDropped:    counterDelta(self.SendDropped, other.SendDropped),
+		RecvDropped:    counterDelta(self.RecvDropped, other.RecvDropped),
+		SendFifoErrors: counterDelta(self.SendFifoErrors, other.SendFifoErrors),
+		RecvFifoErrors: counterDelta(self.RecvFifoErrors, other.RecvFifoErrors),
+
+		RecvFramingErrors: counterDelta(self.RecvFramingErrors, other.RecvFramingErrors),
+		SendCarrier:       counterDelta(self.SendCarrier, other.SendCarrier),
+		SendCollisions:    counterDelta(self.SendCollisions, other.SendCollisions),
+	}
+}
+
 type NetIfaceList struct {
 	List []NetIface
 }
@@ -315,6 +988,13 @@ type NetRawV6ConnList struct {
 	List []NetConn
 }
 
+// Every TCP, UDP and raw socket, with the pids resolved in a single walk of
+// the fds of every process rather than one per protocol
+type NetConnList struct {
+	V4 []NetConn
+	V6 []NetConn // Empty without IPv6
+}
+
 type ProcessList struct {
 	List []Process
 }
@@ -354,6 +1034,54 @@ type ProcState struct {
 	Processor int
 }
 
+// Details of a process from /proc/<pid>/status on Linux. Fields that the
+// kernel doesn't report yet, e.g. RssAnon before 4.5, are left zero.
+type ProcStatus struct {
+	Uids  ProcIds
+	Gids  ProcIds
+	User  string // Name of the effective uid, empty if it isn't in passwd
+	Group string // Name of the effective gid, empty if it isn't in group
+
+	Threads uint64
+
+	// Bytes
+	VmPeak   uint64
+	VmHWM    uint64 // Peak resident set size
+	VmSwap   uint64
+	RssAnon  uint64
+	RssFile  uint64
+	RssShmem uint64
+
+	VoluntaryCtxtSwitches    uint64
+	NonvoluntaryCtxtSwitches uint64
+
+	// Signal masks, where bit n-1 stands for signal n
+	SigPending       uint64 // Pending for the thread
+	SigSharedPending uint64 // Pending for the whole process
+	SigBlocked       uint64
+	SigIgnored       uint64
+	SigCaught        uint64
+
+	// Capability sets, where bit n stands for capability n, e.g. 21 for CAP_SYS_ADMIN
+	CapInheritable uint64
+	CapPermitted   uint64
+	CapEffective   uint64
+	CapBounding    uint64
+	CapAmbient     uint64
+
+	Seccomp     int // 0 when disabled, 1 in strict mode, 2 with filters
+	NoNewPrivs  bool
+	CpusAllowed []int
+}
+
+// User or group ids of a process
+type ProcIds struct {
+	Real       uint32
+	Effective  uint32
+	Saved      uint32
+	FileSystem uint32
+}
+
 type ProcIo struct {
 	ReadBytes  uint64
 	WriteBytes uint64
@@ -361,6 +1089,15 @@ type ProcIo struct {
 	WriteOps   uint64
 }
 
+func (self ProcIo) Delta(other ProcIo) ProcIo {
+	return ProcIo{
+		ReadBytes:  counterDelta(self.ReadBytes, other.ReadBytes),
+		WriteBytes: counterDelta(self.WriteBytes, other.WriteBytes),
+		ReadOps:    counterDelta(self.ReadOps, other.ReadOps),
+		WriteOps:   counterDelta(self.WriteOps, other.WriteOps),
+	}
+}
+
 type ProcMem struct {
 	Size          uint64
 	Resident      uint64
@@ -394,30 +1131,315 @@ type ProcExe struct {
 	Root string
 }
 
+// Open file descriptors of a process against its RLIMIT_NOFILE
+type ProcFd struct {
+	Open      uint64
+	SoftLimit uint64 // RlimInfinity when unlimited
+	HardLimit uint64
+}
+
+// Open file descriptors of every process. Kept out of ProcessList, as it lists
+// the fds and reads the limits of each process.
+type ProcFdUsageList struct {
+	List []ProcFdUsage // Processes whose fds could be read
+}
+
+type ProcFdUsage struct {
+	Pid int
+	ProcFd
+}
+
+// Resource limits of a process. Sizes are in bytes, as setrlimit(2) takes
+// them, except RealtimeTimeout in microseconds and CpuTime in seconds.
+type ProcLimits struct {
+	CpuTime          ProcLimit
+	FileSize         ProcLimit
+	DataSize         ProcLimit
+	StackSize        ProcLimit
+	CoreFileSize     ProcLimit
+	ResidentSet      ProcLimit
+	Processes        ProcLimit
+	OpenFiles        ProcLimit
+	LockedMemory     ProcLimit
+	AddressSpace     ProcLimit
+	FileLocks        ProcLimit
+	PendingSignals   ProcLimit
+	MsgqueueSize     ProcLimit
+	NicePriority     ProcLimit
+	RealtimePriority ProcLimit
+	RealtimeTimeout  ProcLimit
+}
+
+type ProcLimit struct {
+	Soft uint64 // RlimInfinity when unlimited
+	Hard uint64
+}
+
+// The value of unlimited resource limits
+const RlimInfinity = ^uint64(0)
+
+// Open file handles of the whole system, from /proc/sys/fs/file-nr on Linux
+type SystemFd struct {
+	Allocated uint64
+	Unused    uint64 // Allocated but free, always 0 on Linux since 2.6
+	Max       uint64 // fs.file-max
+}
+
+// Open file descriptors of a process, by fd number
+type ProcFdList struct {
+	List []ProcFdEntry
+}
+
+type ProcFdEntry struct {
+	Fd     int
+	Type   ProcFdType
+	Path   string // Target of the fd link, e.g. "/var/log/syslog" or "socket:[12345]"
+	Inode  uint64
+	Flags  uint64   // O_* flags it was opened with
+	Pos    uint64   // File offset
+	MntId  int      // Mount the file is on, the MountId of a FileSystem
+	Socket *NetConn // The TCP, UDP or raw socket, if it is one of those
+}
+
+type ProcFdType int
+
+const (
+	FdFile = ProcFdType(iota + 1)
+	FdDirectory
+	FdDevice
+	FdPipe
+	FdSocket
+	FdEventFd
+	FdAnonInode // Other kernel objects without a file, e.g. epoll or timerfd
+)
+
+func (self ProcFdType) String() string {
+	switch self {
+	case FdFile:
+		return "file"
+	case FdDirectory:
+		return "dir"
+	case FdDevice:
+		return "device"
+	case FdPipe:
+		return "pipe"
+	case FdSocket:
+		return "socket"
+	case FdEventFd:
+		return "eventfd"
+	case FdAnonInode:
+		return "anon_inode"
+	}
+	return ""
+}
+
+// Files that were deleted while still open, which keep their space allocated
+// until every process closes them
+type DeletedOpenFiles struct {
+	List []DeletedOpenFile
+}
+
+type DeletedOpenFile struct {
+	Pid         int
+	ProcessName string
+	Fd          int
+	Path        string // Where the file was before it was deleted
+	Size        uint64 // Bytes
+	Inode       uint64
+	Major       uint64 // Device of the filesystem holding the file
+	Minor       uint64
+	DirName     string // Mount point of that filesystem, empty if it wasn't found
+}
+
+// Reclaimable returns the bytes that closing the files would free, by the
+// mount point they live on. A file open in several places counts once.
+func (self DeletedOpenFiles) Reclaimable() map[string]uint64 {
+	seen := make(map[[3]uint64]bool)
+	reclaimable := make(map[string]uint64)
+	for _, file := range self.List {
+		id := [3]uint64{file.Major, file.Minor, file.Inode}
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		reclaimable[file.DirName] += file.Size
+	}
+	return reclaimable
+}
+
+// Block devices and their partitions, from /sys/block on Linux
+type BlockDeviceList struct {
+	List []BlockDevice // Sorted by name, each disk followed by its partitions
+}
+
+type BlockDevice struct {
+	Name   string // Kernel name, e.g. "nvme0n1" or "dm-0"
+	Major  uint64
+	Minor  uint64
+	Parent string // Disk holding a partition, empty for a whole disk
+
+	Size               uint64 // Bytes
+	LogicalSectorSize  uint64 // Bytes
+	PhysicalSectorSize uint64 // Bytes
+	Rotational         bool
+	Removable          bool
+	ReadOnly           bool
+	Hidden             bool
+
+	Model     string
+	Serial    string
+	Scheduler string // Active I/O scheduler, e.g. "mq-deadline"
+
+	Slaves []string // Devices stacked under a dm or md device
+
+	MapperName    string // Device-mapper name, as in /dev/mapper
+	VolumeGroup   string // For LVM logical volumes
+	LogicalVolume string
+}
+
+func (self BlockDevice) IsPartition() bool {
+	return self.Parent != ""
+}
+
+// Software RAID arrays, from /proc/mdstat on Linux
+type MdArrayList struct {
+	List []MdArray
+}
+
+type MdArray struct {
+	Name   string // e.g. "md0"
+	Active bool
+	State  string // e.g. "clean", "active" or "inactive"
+	Level  string // e.g. "raid1"
+	Size   uint64 // Bytes
+
+	RaidDisks   int // Devices the array should have
+	ActiveDisks int // Devices currently in sync
+	Degraded    int // Missing devices
+	Failed      int // Members marked faulty
+	Spares      int
+	Members     []MdMember
+
+	// Resync, recovery, check or reshape in progress
+	SyncAction    string        // e.g. "recover", "idle" when there is none
+	SyncCompleted float64       // From 0 to 1
+	SyncSpeed     uint64        // Bytes per second
+	SyncEta       time.Duration // Estimated time remaining
+}
+
+type MdMember struct {
+	Name  string // e.g. "sda1"
+	Slot  int    // Role in the array, -1 for spares and faulty devices
+	State string // e.g. "in_sync", "spare" or "faulty"
+}
+
+func (self MdArray) IsDegraded() bool {
+	return self.Degraded > 0 || self.Failed > 0
+}
+
 type DiskList struct {
 	List map[string]DiskIo
 }
 
 type DiskIo struct {
-	ReadOps     uint64
-	ReadBytes   uint64
-	ReadTimeMs  uint64
-	WriteOps    uint64
-	WriteBytes  uint64
-	WriteTimeMs uint64
-	IoTimeMs    uint64
+	ReadOps          uint64
+	ReadMerged       uint64 // Adjacent reads merged into one
+	ReadBytes        uint64
+	ReadTimeMs       uint64
+	WriteOps         uint64
+	WriteMerged      uint64
+	WriteBytes       uint64
+	WriteTimeMs      uint64
+	IoInProgress     uint64 // Currently in flight, not a counter
+	IoTimeMs         uint64 // Time spent with at least one I/O in flight
+	WeightedIoTimeMs uint64 // IoTimeMs weighted by the number of I/Os in flight
+
+	// Since Linux 4.18
+	DiscardOps    uint64
+	DiscardMerged uint64
+	DiscardBytes  uint64
+	DiscardTimeMs uint64
+
+	// Since Linux 5.5
+	FlushOps    uint64
+	FlushTimeMs uint64
 }
 
+// Delta of the counters. IoInProgress keeps its current value.
 func (self DiskIo) Delta(other DiskIo) DiskIo {
 	return DiskIo{
-		ReadOps:     self.ReadOps - other.ReadOps,
-		ReadBytes:   self.ReadBytes - other.ReadBytes,
-		ReadTimeMs:  self.ReadTimeMs - other.ReadTimeMs,
-		WriteOps:    self.WriteOps - other.WriteOps,
-		WriteBytes:  self.WriteBytes - other.WriteBytes,
-		WriteTimeMs: self.WriteTimeMs - other.WriteTimeMs,
-		IoTimeMs:    self.IoTimeMs - other.IoTimeMs,
+		ReadOps:          counterDelta(self.ReadOps, other.ReadOps),
+		ReadMerged:       counterDelta(self.ReadMerged, other.ReadMerged),
+		ReadBytes:        counterDelta(self.ReadBytes, other.ReadBytes),
+		ReadTimeMs:       counterDelta(self.ReadTimeMs, other.ReadTimeMs),
+		WriteOps:         counterDelta(self.WriteOps, other.WriteOps),
+		WriteMerged:      counterDelta(self.WriteMerged, other.WriteMerged),
+		WriteBytes:       counterDelta(self.WriteBytes, other.WriteBytes),
+		WriteTimeMs:      counterDelta(self.WriteTimeMs, other.WriteTimeMs),
+		IoInProgress:     self.IoInProgress,
+		IoTimeMs:         counterDelta(self.IoTimeMs, other.IoTimeMs),
+		WeightedIoTimeMs: counterDelta(self.WeightedIoTimeMs, other.WeightedIoTimeMs),
+		DiscardOps:       counterDelta(self.DiscardOps, other.DiscardOps),
+		DiscardMerged:    counterDelta(self.DiscardMerged, other.DiscardMerged),
+		DiscardBytes:     counterDelta(self.DiscardBytes, other.DiscardBytes),
+		DiscardTimeMs:    counterDelta(self.DiscardTimeMs, other.DiscardTimeMs),
+		FlushOps:         counterDelta(self.FlushOps, other.FlushOps),
+		FlushTimeMs:      counterDelta(self.FlushTimeMs, other.FlushTimeMs),
+	}
+}
+
+// IoStat derives the extended iostat metrics of the device from a previous
+// sample taken elapsed earlier
+func (self DiskIo) IoStat(previous DiskIo, elapsed time.Duration) DiskIoStat {
+	ms := float64(elapsed.Nanoseconds()) / 1e6
+	if ms <= 0 {
+		return DiskIoStat{}
+	}
+	seconds := ms / 1000
+	delta := self.Delta(previous)
+
+	stat := DiskIoStat{
+		ReadsPerSec:        float64(delta.ReadOps) / seconds,
+		WritesPerSec:       float64(delta.WriteOps) / seconds,
+		ReadMergesPerSec:   float64(delta.ReadMerged) / seconds,
+		WriteMergesPerSec:  float64(delta.WriteMerged) / seconds,
+		ReadKBytesPerSec:   float64(delta.ReadBytes) / 1024 / seconds,
+		WriteKBytesPerSec:  float64(delta.WriteBytes) / 1024 / seconds,
+		AverageQueueLength: float64(delta.WeightedIoTimeMs) / ms,
+		Utilization:        100 * float64(delta.IoTimeMs) / ms,
 	}
+	if stat.Utilization > 100 {
+		stat.Utilization = 100
+	}
+
+	if delta.ReadOps > 0 {
+		stat.ReadAwait = float64(delta.ReadTimeMs) / float64(delta.ReadOps)
+	}
+	if delta.WriteOps > 0 {
+		stat.WriteAwait = float64(delta.WriteTimeMs) / float64(delta.WriteOps)
+	}
+	ops := delta.ReadOps + delta.WriteOps + delta.DiscardOps
+	if ops > 0 {
+		stat.Await = float64(delta.ReadTimeMs+delta.WriteTimeMs+delta.DiscardTimeMs) / float64(ops)
+		stat.ServiceTime = float64(delta.IoTimeMs) / float64(ops)
+	}
+	return stat
+}
+
+// Extended device statistics, as reported by iostat -x
+type DiskIoStat struct {
+	ReadsPerSec        float64 // r/s
+	WritesPerSec       float64 // w/s
+	ReadMergesPerSec   float64 // rrqm/s
+	WriteMergesPerSec  float64 // wrqm/s
+	ReadKBytesPerSec   float64 // rkB/s
+	WriteKBytesPerSec  float64 // wkB/s
+	Await              float64 // await, ms per read, write or discard
+	ReadAwait          float64 // r_await, ms
+	WriteAwait         float64 // w_await, ms
+	AverageQueueLength float64 // aqu-sz
+	Utilization        float64 // %util, from 0 to 100
+	ServiceTime        float64 // svctm, ms
 }
 
 type SystemInfo struct {
@@ -1,6 +1,7 @@
 package sigar_test
 
 import (
+	"context"
 	"net"
 	"os"
 	"path/filepath"
@@ -39,6 +40,83 @@ var _ = Describe("Sigar", func() {
 		}
 	})
 
+	It("cpu percent", func() {
+		cpu := Cpu{User: 60, Sys: 20, Idle: 10, Wait: 10, Guest: 30}
+		percent := cpu.Percent()
+		Expect(percent.User).To(Equal(0.6))
+		Expect(percent.Guest).To(Equal(0.3))
+		Expect(percent.Busy()).To(BeNumerically("~", 0.8, 1e-9))
+
+		Expect(Cpu{}.Percent()).To(Equal(CpuPercent{}))
+	})
+
+	It("kernel stat", func() {
+		kernelStat := KernelStat{}
+		err := kernelStat.Get()
+		if runtime.GOOS == "linux" {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(kernelStat.ContextSwitches).To(BeNumerically(">", 0))
+		} else {
+			Expect(err).To(Equal(ErrNotImplemented))
+		}
+	})
+
+	It("pressure", func() {
+		pressure := Pressure{}
+		err := pressure.Get()
+		if runtime.GOOS == "linux" {
+			if !IsNotSupported(err) {
+				Expect(err).ToNot(HaveOccurred())
+			}
+		} else {
+			Expect(err).To(Equal(ErrNotImplemented))
+		}
+	})
+
+	It("vm stat", func() {
+		vmstat := VmStat{}
+		err := vmstat.Get()
+		if runtime.GOOS == "linux" {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(vmstat.PgFault).To(BeNumerically(">", 0))
+		} else {
+			Expect(err).To(Equal(ErrNotImplemented))
+		}
+	})
+
+	It("swap list", func() {
+		swapList := SwapList{}
+		err := swapList.Get()
+		if runtime.GOOS == "linux" {
+			Expect(err).ToNot(HaveOccurred())
+		} else {
+			Expect(err).To(Equal(ErrNotImplemented))
+		}
+	})
+
+	It("cpu info", func() {
+		cpuInfo := CpuInfo{}
+		err := cpuInfo.Get()
+		if runtime.GOOS == "linux" {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(cpuInfo.Online()).To(BeNumerically(">", 0))
+		} else {
+			Expect(err).To(Equal(ErrNotImplemented))
+		}
+	})
+
+	It("numa node list", func() {
+		nodeList := NumaNodeList{}
+		err := nodeList.Get()
+		if runtime.GOOS == "linux" {
+			if !IsNotSupported(err) {
+				Expect(err).ToNot(HaveOccurred())
+			}
+		} else {
+			Expect(err).To(Equal(ErrNotImplemented))
+		}
+	})
+
 	It("load average", func() {
 		avg := LoadAverage{}
 		err := avg.Get()
@@ -99,11 +177,40 @@ var _ = Describe("Sigar", func() {
 		fsusage := FileSystemUsage{}
 		err := fsusage.Get("/")
 		Expect(err).ToNot(HaveOccurred())
+		if runtime.GOOS != "windows" {
+			Expect(fsusage.FragmentSize).To(BeNumerically(">", 0))
+			Expect(fsusage.Total % fsusage.FragmentSize).To(BeZero())
+			Expect(fsusage.Free).To(Equal(fsusage.Avail + fsusage.Reserved))
+		}
 
 		err = fsusage.Get("T O T A L L Y B O G U S")
 		Expect(err).To(HaveOccurred())
 	})
 
+	It("file system usage percentages", func() {
+		fsusage := FileSystemUsage{Total: 1000, Free: 300, Avail: 200, Files: 200, FreeFiles: 50}
+		Expect(fsusage.UsedFiles()).To(Equal(uint64(150)))
+		Expect(fsusage.FilesUsePercent()).To(Equal(75.0))
+		Expect(FileSystemUsage{}.UsedFiles()).To(BeZero())
+	})
+
+	It("file system usage with context", func() {
+		fsusage := FileSystemUsage{}
+		err := fsusage.GetContext(context.Background(), "/")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(fsusage.Total).To(BeNumerically(">", 0))
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		err = fsusage.GetContext(ctx, "T O T A L L Y B O G U S")
+		Expect(err).To(HaveOccurred())
+
+		ctx, cancel = context.WithCancel(context.Background())
+		cancel()
+		err = fsusage.GetContext(ctx, "/")
+		Expect(err).To(Equal(context.Canceled))
+	})
+
 	It("net proto v4", func() {
 		net := NetProtoV4Stats{}
 		err := net.Get()
@@ -277,6 +384,41 @@ var _ = Describe("Sigar", func() {
 		Expect([]string{"go", "go.exe", "ginkgo"}).To(ContainElement(filepath.Base(exe.Name)))
 	})
 
+	It("proc fd list", func() {
+		fdList := ProcFdList{}
+		err := fdList.Get(os.Getpid())
+		if runtime.GOOS == "linux" {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(len(fdList.List)).To(BeNumerically(">=", 3))
+		} else {
+			Expect(err).To(Equal(ErrNotImplemented))
+		}
+	})
+
+	It("proc status", func() {
+		status := ProcStatus{}
+		err := status.Get(os.Getpid())
+		if runtime.GOOS == "linux" {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(status.Uids.Effective).To(Equal(uint32(os.Geteuid())))
+			Expect(status.Threads).To(BeNumerically(">=", 1))
+		} else {
+			Expect(err).To(Equal(ErrNotImplemented))
+		}
+	})
+
+	It("proc fd", func() {
+		fd := ProcFd{}
+		err := fd.Get(os.Getpid())
+		if runtime.GOOS == "linux" {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(fd.Open).To(BeNumerically(">=", 3))
+			Expect(fd.Open).To(BeNumerically("<=", fd.SoftLimit))
+		} else {
+			Expect(err).To(Equal(ErrNotImplemented))
+		}
+	})
+
 	It("disk list", func() {
 		disk := DiskList{}
 		err := disk.Get()
@@ -287,6 +429,56 @@ var _ = Describe("Sigar", func() {
 		}
 	})
 
+	It("block device list", func() {
+		blockDevices := BlockDeviceList{}
+		err := blockDevices.Get()
+		if runtime.GOOS == "linux" {
+			Expect(err).ToNot(HaveOccurred())
+		} else {
+			Expect(err).To(Equal(ErrNotImplemented))
+		}
+	})
+
+	It("md array list", func() {
+		mdArrays := MdArrayList{}
+		err := mdArrays.Get()
+		if runtime.GOOS == "linux" {
+			if !IsNotSupported(err) {
+				Expect(err).ToNot(HaveOccurred())
+			}
+		} else {
+			Expect(err).To(Equal(ErrNotImplemented))
+		}
+	})
+
+	It("disk io stat", func() {
+		previous := DiskIo{ReadOps: 100, WriteOps: 50, ReadTimeMs: 1000, WriteTimeMs: 500, IoTimeMs: 10000}
+		current := DiskIo{
+			ReadOps:          300,
+			ReadBytes:        2048 * 1024,
+			ReadTimeMs:       1400,
+			WriteOps:         250,
+			WriteTimeMs:      2500,
+			IoInProgress:     3,
+			IoTimeMs:         11000,
+			WeightedIoTimeMs: 4000,
+		}
+
+		stat := current.IoStat(previous, 2*time.Second)
+		Expect(stat.ReadsPerSec).To(Equal(100.0))
+		Expect(stat.WritesPerSec).To(Equal(100.0))
+		Expect(stat.ReadKBytesPerSec).To(Equal(1024.0))
+		Expect(stat.ReadAwait).To(Equal(2.0))
+		Expect(stat.WriteAwait).To(Equal(10.0))
+		Expect(stat.Await).To(Equal(6.0))
+		Expect(stat.AverageQueueLength).To(Equal(2.0))
+		Expect(stat.Utilization).To(Equal(50.0))
+		Expect(stat.ServiceTime).To(Equal(2.5))
+
+		Expect(current.Delta(previous).IoInProgress).To(Equal(uint64(3)))
+		Expect(current.IoStat(previous, 0)).To(Equal(DiskIoStat{}))
+	})
+
 	It("system info", func() {
 		info := SystemInfo{}
 		err := info.Get()
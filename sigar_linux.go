@@ -5,6 +5,7 @@ package sigar
 import (
 	"bufio"
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"io"
@@ -13,37 +14,100 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"syscall"
 	"time"
 )
 
-var system struct {
-	ticks uint64
-	btime uint64
-}
-
+// Default roots used by the package-level Get() methods. Changing these
+// affects every collector that was not created with its own roots via New.
 var Procd string
 var Sysd string
 var Etcd string
 
 const readAllDirnames = -1 // see os.File.Readdirnames doc
 
-func init() {
-	system.ticks = 100 // C.sysconf(C._SC_CLK_TCK)
+// Options configures a LinuxSigar. Empty roots fall back to the package-level
+// Procd, Sysd and Etcd values at the time of each collection.
+type Options struct {
+	ProcRoot string
+	SysRoot  string
+	EtcRoot  string
+}
 
+// LinuxSigar collects system statistics relative to its own proc, sys and
+// etc roots, so that several collectors (e.g. one for the host mounted at
+// /host/proc and one for the container) can coexist in one process.
+type LinuxSigar struct {
+	procRoot string
+	sysRoot  string
+	etcRoot  string
+
+	ticks uint64
+	btime uint64
+
+	netConns netConnCache
+}
+
+// The instance backing ConcreteSigar and the package-level Get() methods.
+var defaultSigar = &LinuxSigar{}
+
+func init() {
 	Procd = "/proc"
 	Sysd = "/sys"
 	Etcd = "/etc"
+
+	defaultSigar.ticks = 100 // C.sysconf(C._SC_CLK_TCK)
 	LoadStartTime()
 }
 
+// New returns a collector that reads from the roots given in opts.
+func New(opts Options) *LinuxSigar {
+	s := &LinuxSigar{
+		procRoot: opts.ProcRoot,
+		sysRoot:  opts.SysRoot,
+		etcRoot:  opts.EtcRoot,
+		ticks:    100, // C.sysconf(C._SC_CLK_TCK)
+	}
+	s.LoadStartTime()
+	return s
+}
+
+func (s *LinuxSigar) procd() string {
+	if s.procRoot != "" {
+		return s.procRoot
+	}
+	return Procd
+}
+
+func (s *LinuxSigar) sysd() string {
+	if s.sysRoot != "" {
+		return s.sysRoot
+	}
+	return Sysd
+}
+
+func (s *LinuxSigar) etcd() string {
+	if s.etcRoot != "" {
+		return s.etcRoot
+	}
+	return Etcd
+}
+
+// LoadStartTime reloads the boot time of the default collector from Procd.
 func LoadStartTime() {
+	defaultSigar.LoadStartTime()
+}
+
+// LoadStartTime reloads the boot time used to convert process start times.
+func (s *LinuxSigar) LoadStartTime() {
 	// grab system boot time
-	readFile(Procd+"/stat", func(line string) bool {
+	readFile(s.procd()+"/stat", func(line string) bool {
 		if strings.HasPrefix(line, "btime") {
-			system.btime, _ = strtoull(line[6:])
+			s.btime, _ = strtoull(line[6:])
 			return false // stop reading
 		}
 		return true
@@ -51,16 +115,32 @@ func LoadStartTime() {
 }
 
 func (self *LoadAverage) Get() error {
-	line, err := ioutil.ReadFile(Procd + "/loadavg")
+	return self.get(defaultSigar)
+}
+
+func (self *LoadAverage) get(s *LinuxSigar) error {
+	path := s.procd() + "/loadavg"
+	line, err := ioutil.ReadFile(path)
 	if err != nil {
-		return nil
+		return readError(path, err, false)
 	}
 
 	fields := strings.Fields(string(line))
+	if len(fields) < 3 {
+		return parseError(path, "", fmt.Errorf("Expected 3 load averages, got %d fields", len(fields)))
+	}
 
-	self.One, _ = strconv.ParseFloat(fields[0], 64)
-	self.Five, _ = strconv.ParseFloat(fields[1], 64)
-	self.Fifteen, _ = strconv.ParseFloat(fields[2], 64)
+	averages := map[string]*float64{
+		"One":     &self.One,
+		"Five":    &self.Five,
+		"Fifteen": &self.Fifteen,
+	}
+	for i, name := range []string{"One", "Five", "Fifteen"} {
+		*averages[name], err = strconv.ParseFloat(fields[i], 64)
+		if err != nil {
+			return parseError(path, name, err)
+		}
+	}
 
 	return nil
 }
@@ -78,33 +158,182 @@ func (self *Uptime) Get() error {
 }
 
 func (self *Mem) Get() error {
-	var buffers, cached uint64
-	table := map[string]*uint64{
-		"MemTotal": &self.Total,
-		"MemFree":  &self.Free,
-		"Buffers":  &buffers,
-		"Cached":   &cached,
-	}
+	return self.get(defaultSigar)
+}
 
-	if err := parseMeminfo(table); err != nil {
+func (self *Mem) get(s *LinuxSigar) error {
+	info := MemInfo{}
+	if err := info.get(s); err != nil {
 		return err
 	}
 
+	self.Total = info.MemTotal
+	self.Free = info.MemFree
 	self.Used = self.Total - self.Free
-	kern := buffers + cached
-	self.ActualFree = self.Free + kern
-	self.ActualUsed = self.Used - kern
+	self.ActualFree = info.actualFree()
+	self.ActualUsed = self.Total - self.ActualFree
 
 	return nil
 }
 
+// Memory available for new allocations without swapping. Kernels before 3.14
+// don't report MemAvailable, so estimate it like the kernel does: page cache
+// and reclaimable slab can be freed, but shmem (e.g. tmpfs) in the cache can't.
+func (self *MemInfo) actualFree() uint64 {
+	if self.HasMemAvailable {
+		return self.MemAvailable
+	}
+
+	free := self.MemFree + self.Buffers + self.Cached + self.SReclaimable
+	if self.Shmem < free {
+		free -= self.Shmem
+	}
+	if free > self.MemTotal {
+		free = self.MemTotal
+	}
+	return free
+}
+
+func (self *MemInfo) Get() error {
+	return self.get(defaultSigar)
+}
+
+func (self *MemInfo) get(s *LinuxSigar) error {
+	table := map[string]*uint64{
+		"MemTotal":          &self.MemTotal,
+		"MemFree":           &self.MemFree,
+		"MemAvailable":      &self.MemAvailable,
+		"Buffers":           &self.Buffers,
+		"Cached":            &self.Cached,
+		"SwapCached":        &self.SwapCached,
+		"Active":            &self.Active,
+		"Inactive":          &self.Inactive,
+		"Active(anon)":      &self.ActiveAnon,
+		"Inactive(anon)":    &self.InactiveAnon,
+		"Active(file)":      &self.ActiveFile,
+		"Inactive(file)":    &self.InactiveFile,
+		"Unevictable":       &self.Unevictable,
+		"Mlocked":           &self.Mlocked,
+		"SwapTotal":         &self.SwapTotal,
+		"SwapFree":          &self.SwapFree,
+		"Dirty":             &self.Dirty,
+		"Writeback":         &self.Writeback,
+		"AnonPages":         &self.AnonPages,
+		"Mapped":            &self.Mapped,
+		"Shmem":             &self.Shmem,
+		"KReclaimable":      &self.KReclaimable,
+		"Slab":              &self.Slab,
+		"SReclaimable":      &self.SReclaimable,
+		"SUnreclaim":        &self.SUnreclaim,
+		"KernelStack":       &self.KernelStack,
+		"PageTables":        &self.PageTables,
+		"NFS_Unstable":      &self.NFSUnstable,
+		"Bounce":            &self.Bounce,
+		"WritebackTmp":      &self.WritebackTmp,
+		"CommitLimit":       &self.CommitLimit,
+		"Committed_AS":      &self.CommittedAS,
+		"VmallocTotal":      &self.VmallocTotal,
+		"VmallocUsed":       &self.VmallocUsed,
+		"VmallocChunk":      &self.VmallocChunk,
+		"Percpu":            &self.Percpu,
+		"HardwareCorrupted": &self.HardwareCorrupted,
+		"AnonHugePages":     &self.AnonHugePages,
+		"ShmemHugePages":    &self.ShmemHugePages,
+		"ShmemPmdMapped":    &self.ShmemPmdMapped,
+		"FileHugePages":     &self.FileHugePages,
+		"FilePmdMapped":     &self.FilePmdMapped,
+		"CmaTotal":          &self.CmaTotal,
+		"CmaFree":           &self.CmaFree,
+		"HugePages_Total":   &self.HugePagesTotal,
+		"HugePages_Free":    &self.HugePagesFree,
+		"HugePages_Rsvd":    &self.HugePagesRsvd,
+		"HugePages_Surp":    &self.HugePagesSurp,
+		"Hugepagesize":      &self.Hugepagesize,
+		"Hugetlb":           &self.Hugetlb,
+		"DirectMap4k":       &self.DirectMap4k,
+		"DirectMap2M":       &self.DirectMap2M,
+		"DirectMap1G":       &self.DirectMap1G,
+	}
+
+	return s.parseMeminfo(table, func(key string, value uint64) {
+		if key == "MemAvailable" {
+			self.HasMemAvailable = true
+		}
+		if _, ok := table[key]; ok {
+			return
+		}
+		if self.Other == nil {
+			self.Other = make(map[string]uint64)
+		}
+		self.Other[key] = value
+	})
+}
+
+func (self *VmStat) Get() error {
+	return self.get(defaultSigar)
+}
+
+func (self *VmStat) get(s *LinuxSigar) error {
+	vmstatFile := s.procd() + "/vmstat"
+	table := map[string]*uint64{
+		"pgpgin":                    &self.PgpgIn,
+		"pgpgout":                   &self.PgpgOut,
+		"pswpin":                    &self.PswpIn,
+		"pswpout":                   &self.PswpOut,
+		"pgfault":                   &self.PgFault,
+		"pgmajfault":                &self.PgMajFault,
+		"pgscan_kswapd":             &self.PgScanKswapd,
+		"pgscan_direct":             &self.PgScanDirect,
+		"pgsteal_kswapd":            &self.PgStealKswapd,
+		"pgsteal_direct":            &self.PgStealDirect,
+		"oom_kill":                  &self.OomKill,
+		"thp_fault_alloc":           &self.ThpFaultAlloc,
+		"thp_fault_fallback":        &self.ThpFaultFallback,
+		"thp_collapse_alloc":        &self.ThpCollapseAlloc,
+		"thp_collapse_alloc_failed": &self.ThpCollapseAllocFailed,
+		"thp_split_page":            &self.ThpSplitPage,
+	}
+
+	var parseErr error
+	err := readFile(vmstatFile, func(line string) bool {
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			return true
+		}
+
+		value, err := strtoull(fields[1])
+		if err != nil {
+			parseErr = parseError(vmstatFile, fields[0], err)
+			return false
+		}
+
+		if ptr := table[fields[0]]; ptr != nil {
+			*ptr = value
+		} else {
+			if self.Other == nil {
+				self.Other = make(map[string]uint64)
+			}
+			self.Other[fields[0]] = value
+		}
+		return true
+	})
+	if err != nil {
+		return err
+	}
+	return parseErr
+}
+
 func (self *Swap) Get() error {
+	return self.get(defaultSigar)
+}
+
+func (self *Swap) get(s *LinuxSigar) error {
 	table := map[string]*uint64{
 		"SwapTotal": &self.Total,
 		"SwapFree":  &self.Free,
 	}
 
-	if err := parseMeminfo(table); err != nil {
+	if err := s.parseMeminfo(table, nil); err != nil {
 		return err
 	}
 
@@ -112,8 +341,272 @@ func (self *Swap) Get() error {
 	return nil
 }
 
+func (self *NumaNodeList) Get() error {
+	return self.get(defaultSigar)
+}
+
+// Returns an ErrNotSupported *Error on kernels built without NUMA support
+func (self *NumaNodeList) get(s *LinuxSigar) error {
+	nodeDir := s.sysd() + "/devices/system/node"
+
+	entries, err := ioutil.ReadDir(nodeDir)
+	if os.IsNotExist(err) {
+		return &Error{Kind: ErrNotSupported, Path: nodeDir, Err: err}
+	}
+	if err != nil {
+		return readError(nodeDir, err, false)
+	}
+
+	list := make([]NumaNode, 0, 2)
+	for _, entry := range entries {
+		if !strings.HasPrefix(entry.Name(), "node") {
+			continue
+		}
+		id, err := strconv.Atoi(entry.Name()[4:])
+		if err != nil {
+			continue
+		}
+
+		node := NumaNode{Id: id}
+		if err := node.read(nodeDir + "/" + entry.Name()); err != nil {
+			return err
+		}
+		list = append(list, node)
+	}
+
+	sort.Sort(numaNodesById(list))
+	self.List = list
+	return nil
+}
+
+type numaNodesById []NumaNode
+
+func (self numaNodesById) Len() int           { return len(self) }
+func (self numaNodesById) Less(i, j int) bool { return self[i].Id < self[j].Id }
+func (self numaNodesById) Swap(i, j int)      { self[i], self[j] = self[j], self[i] }
+
+func (self *NumaNode) read(dir string) error {
+	cpulistFile := dir + "/cpulist"
+	contents, err := ioutil.ReadFile(cpulistFile)
+	if err != nil {
+		return readError(cpulistFile, err, false)
+	}
+	self.Cpus, err = parseCpuList(strings.TrimSpace(string(contents)))
+	if err != nil {
+		return parseError(cpulistFile, "", err)
+	}
+
+	distanceFile := dir + "/distance"
+	contents, err = ioutil.ReadFile(distanceFile)
+	if err != nil {
+		return readError(distanceFile, err, false)
+	}
+	for _, field := range strings.Fields(string(contents)) {
+		distance, err := strconv.Atoi(field)
+		if err != nil {
+			return parseError(distanceFile, "", err)
+		}
+		self.Distances = append(self.Distances, distance)
+	}
+
+	// Lines look like "Node 0 MemTotal:       16323800 kB"
+	meminfoFile := dir + "/meminfo"
+	table := map[string]*uint64{
+		"MemTotal:": &self.MemTotal,
+		"MemFree:":  &self.MemFree,
+		"MemUsed:":  &self.MemUsed,
+	}
+	var parseErr error
+	err = readFile(meminfoFile, func(line string) bool {
+		fields := strings.Fields(line)
+		if len(fields) < 4 {
+			return true
+		}
+		if ptr := table[fields[2]]; ptr != nil {
+			value, err := strtoull(fields[3])
+			if err != nil {
+				parseErr = parseError(meminfoFile, strings.TrimSuffix(fields[2], ":"), err)
+				return false
+			}
+			*ptr = value * 1024
+		}
+		return true
+	})
+	if err != nil {
+		return err
+	}
+	if parseErr != nil {
+		return parseErr
+	}
+
+	numastatFile := dir + "/numastat"
+	table = map[string]*uint64{
+		"numa_hit":       &self.NumaStat.NumaHit,
+		"numa_miss":      &self.NumaStat.NumaMiss,
+		"numa_foreign":   &self.NumaStat.NumaForeign,
+		"interleave_hit": &self.NumaStat.InterleaveHit,
+		"local_node":     &self.NumaStat.LocalNode,
+		"other_node":     &self.NumaStat.OtherNode,
+	}
+	err = readFile(numastatFile, func(line string) bool {
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			return true
+		}
+		if ptr := table[fields[0]]; ptr != nil {
+			value, err := strtoull(fields[1])
+			if err != nil {
+				parseErr = parseError(numastatFile, fields[0], err)
+				return false
+			}
+			*ptr = value
+		}
+		return true
+	})
+	if err != nil {
+		return err
+	}
+	return parseErr
+}
+
+// Parse a CPU list like "0-3,8,10-11", as in cpulist files
+func parseCpuList(list string) ([]int, error) {
+	cpus := []int{}
+	if list == "" {
+		return cpus, nil
+	}
+
+	for _, item := range strings.Split(list, ",") {
+		bounds := strings.SplitN(item, "-", 2)
+		first, err := strconv.Atoi(bounds[0])
+		if err != nil {
+			return nil, err
+		}
+		last := first
+		if len(bounds) == 2 {
+			last, err = strconv.Atoi(bounds[1])
+			if err != nil {
+				return nil, err
+			}
+		}
+		for cpu := first; cpu <= last; cpu++ {
+			cpus = append(cpus, cpu)
+		}
+	}
+	return cpus, nil
+}
+
+func (self *SwapList) Get() error {
+	return self.get(defaultSigar)
+}
+
+func (self *SwapList) get(s *LinuxSigar) error {
+	swapsFile := s.procd() + "/swaps"
+	list := make([]SwapDevice, 0, 2)
+
+	var parseErr error
+	err := readFile(swapsFile, func(line string) bool {
+		fields := strings.Fields(line)
+		// Skip the header
+		if len(fields) < 5 || fields[0] == "Filename" {
+			return true
+		}
+
+		device := SwapDevice{
+			Name: unescapeOctal(fields[0]),
+			Type: fields[1],
+		}
+
+		// Sizes are in KiB
+		size, err := strtoull(fields[2])
+		if err != nil {
+			parseErr = parseError(swapsFile, "Size", err)
+			return false
+		}
+		used, err := strtoull(fields[3])
+		if err != nil {
+			parseErr = parseError(swapsFile, "Used", err)
+			return false
+		}
+		device.Size = size * 1024
+		device.Used = used * 1024
+
+		device.Priority, err = strconv.Atoi(fields[4])
+		if err != nil {
+			parseErr = parseError(swapsFile, "Priority", err)
+			return false
+		}
+
+		if strings.HasPrefix(device.Name, "/dev/zram") {
+			device.IsZram = true
+			device.Zram, parseErr = s.readZramStat(filepath.Base(device.Name))
+			if parseErr != nil {
+				return false
+			}
+		}
+
+		list = append(list, device)
+		return true
+	})
+	if err != nil {
+		return err
+	}
+	if parseErr != nil {
+		return parseErr
+	}
+
+	self.List = list
+	return nil
+}
+
+// Read /sys/block/<name>/mm_stat. Kernels before 4.1 don't have it, so its
+// stats are left empty.
+func (s *LinuxSigar) readZramStat(name string) (ZramStat, error) {
+	stat := ZramStat{}
+	mmStatFile := s.sysd() + "/block/" + name + "/mm_stat"
+
+	contents, err := ioutil.ReadFile(mmStatFile)
+	if os.IsNotExist(err) {
+		return stat, nil
+	}
+	if err != nil {
+		return stat, readError(mmStatFile, err, false)
+	}
+
+	columns := []struct {
+		name  string
+		field *uint64
+	}{
+		{"orig_data_size", &stat.OrigDataSize},
+		{"compr_data_size", &stat.ComprDataSize},
+		{"mem_used_total", &stat.MemUsedTotal},
+		{"mem_limit", &stat.MemLimit},
+		{"mem_used_max", &stat.MemUsedMax},
+		{"same_pages", &stat.SamePages},
+		{"pages_compacted", &stat.PagesCompacted},
+		{"huge_pages", &stat.HugePages},
+	}
+
+	fields := strings.Fields(string(contents))
+	for i, column := range columns {
+		// Older kernels have fewer columns
+		if i >= len(fields) {
+			break
+		}
+		*column.field, err = strtoull(fields[i])
+		if err != nil {
+			return stat, parseError(mmStatFile, column.name, err)
+		}
+	}
+	return stat, nil
+}
+
 func (self *Cpu) Get() error {
-	return readFile(Procd+"/stat", func(line string) bool {
+	return self.get(defaultSigar)
+}
+
+func (self *Cpu) get(s *LinuxSigar) error {
+	return readFile(s.procd()+"/stat", func(line string) bool {
 		if len(line) > 4 && line[0:4] == "cpu " {
 			parseCpuStat(self, line)
 			return false
@@ -123,18 +616,171 @@ func (self *Cpu) Get() error {
 	})
 }
 
+func (self *Pressure) Get() error {
+	return self.get(defaultSigar)
+}
+
+// Returns an ErrNotSupported *Error on kernels without PSI, i.e. before 4.20
+// or booted with psi=0
+func (self *Pressure) get(s *LinuxSigar) error {
+	dir := s.procd() + "/pressure"
+	return self.read(dir+"/cpu", dir+"/memory", dir+"/io")
+}
+
+// GetCgroup gets the pressure of a cgroup v2 group, given its path relative
+// to the cgroup2 mount under the sys root, e.g. "system.slice/sshd.service".
+// The mount is fs/cgroup/unified on hybrid hierarchies, or else fs/cgroup.
+func (self *Pressure) GetCgroup(path string) error {
+	return self.getCgroup(defaultSigar, path)
+}
+
+func (self *Pressure) getCgroup(s *LinuxSigar, path string) error {
+	var err error
+	for _, mount := range []string{"fs/cgroup/unified", "fs/cgroup"} {
+		dir := filepath.Join(s.sysd(), mount, path)
+		err = self.read(dir+"/cpu.pressure", dir+"/memory.pressure", dir+"/io.pressure")
+		if !IsNotSupported(err) {
+			return err
+		}
+	}
+	return err
+}
+
+func (self *Pressure) read(cpuFile, memoryFile, ioFile string) error {
+	if err := readPressure(cpuFile, &self.Cpu); err != nil {
+		return err
+	}
+	if err := readPressure(memoryFile, &self.Memory); err != nil {
+		return err
+	}
+	return readPressure(ioFile, &self.Io)
+}
+
+// Parse lines like "some avg10=0.12 avg60=0.05 avg300=0.01 total=123456"
+func readPressure(file string, resource *PressureResource) error {
+	contents, err := ioutil.ReadFile(file)
+	if os.IsNotExist(err) || isErrno(err, syscall.EOPNOTSUPP) {
+		return &Error{Kind: ErrNotSupported, Path: file, Err: err}
+	}
+	if err != nil {
+		return readError(file, err, false)
+	}
+
+	for _, line := range strings.Split(string(contents), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		var stat *PressureStat
+		switch fields[0] {
+		case "some":
+			stat = &resource.Some
+		case "full":
+			stat = &resource.Full
+		default:
+			continue
+		}
+
+		for _, field := range fields[1:] {
+			parts := strings.SplitN(field, "=", 2)
+			if len(parts) != 2 {
+				return parseError(file, fields[0]+" "+field, nil)
+			}
+
+			var err error
+			switch parts[0] {
+			case "avg10":
+				stat.Avg10, err = strconv.ParseFloat(parts[1], 64)
+			case "avg60":
+				stat.Avg60, err = strconv.ParseFloat(parts[1], 64)
+			case "avg300":
+				stat.Avg300, err = strconv.ParseFloat(parts[1], 64)
+			case "total":
+				stat.Total, err = strtoull(parts[1])
+			}
+			if err != nil {
+				return parseError(file, fields[0]+" "+parts[0], err)
+			}
+		}
+	}
+	return nil
+}
+
+func (self *KernelStat) Get() error {
+	return self.get(defaultSigar)
+}
+
+func (self *KernelStat) get(s *LinuxSigar) error {
+	statFile := s.procd() + "/stat"
+
+	var parseErr error
+	err := readFile(statFile, func(line string) bool {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			return true
+		}
+
+		var field *uint64
+		switch fields[0] {
+		case "ctxt":
+			field = &self.ContextSwitches
+		case "intr":
+			field = &self.Interrupts
+		case "softirq":
+			field = &self.SoftIrqs
+		case "processes":
+			field = &self.Forks
+		case "procs_running":
+			field = &self.ProcsRunning
+		case "procs_blocked":
+			field = &self.ProcsBlocked
+		case "btime":
+			field = &self.BootTime
+		default:
+			return true
+		}
+
+		// intr and softirq are followed by per-source counts; the first is the total
+		value, err := strtoull(fields[1])
+		if err != nil {
+			parseErr = parseError(statFile, fields[0], err)
+			return false
+		}
+		*field = value
+		return true
+	})
+	if err != nil {
+		return err
+	}
+	return parseErr
+}
+
 func (self *CpuList) Get() error {
+	return self.get(defaultSigar)
+}
+
+func (self *CpuList) get(s *LinuxSigar) error {
 	capacity := len(self.List)
 	if capacity == 0 {
 		capacity = 4
 	}
 	list := make([]Cpu, 0, capacity)
 
-	err := readFile(Procd+"/stat", func(line string) bool {
+	err := readFile(s.procd()+"/stat", func(line string) bool {
 		if len(line) > 3 && line[0:3] == "cpu" && line[3] != ' ' {
 			cpu := Cpu{}
 			parseCpuStat(&cpu, line)
-			list = append(list, cpu)
+
+			// Offline CPUs are missing, so index by the CPU id
+			id, err := strconv.Atoi(strings.Fields(line)[0][3:])
+			if err != nil {
+				id = len(list)
+			}
+			for len(list) <= id {
+				list = append(list, Cpu{})
+			}
+			list[id] = cpu
 		}
 		return true
 	})
@@ -143,8 +789,150 @@ func (self *CpuList) Get() error {
 
 	return err
 }
+
+func (self *CpuInfo) Get() error {
+	return self.get(defaultSigar)
+}
+
+func (self *CpuInfo) get(s *LinuxSigar) error {
+	cpus := map[int]*LogicalCpu{}
+	cpu := func(id int) *LogicalCpu {
+		if cpus[id] == nil {
+			cpus[id] = &LogicalCpu{Id: id, SocketId: -1, CoreId: -1, ThreadId: -1}
+		}
+		return cpus[id]
+	}
+
+	// Only online CPUs are listed, in blocks starting with "processor"
+	var current *LogicalCpu
+	err := readFile(s.procd()+"/cpuinfo", func(line string) bool {
+		fields := strings.SplitN(line, ":", 2)
+		if len(fields) != 2 {
+			return true
+		}
+		key := strings.TrimSpace(fields[0])
+		value := strings.TrimSpace(fields[1])
+
+		if key == "processor" {
+			id, err := strconv.Atoi(value)
+			if err != nil {
+				current = nil
+				return true
+			}
+			current = cpu(id)
+			current.Online = true
+			return true
+		}
+		if current == nil {
+			return true
+		}
+
+		switch key {
+		case "vendor_id":
+			current.Vendor = value
+		case "model name":
+			current.ModelName = value
+		case "flags", "Features":
+			current.Flags = strings.Fields(value)
+		case "physical id":
+			current.SocketId = atoiOr(value, -1)
+		case "core id":
+			current.CoreId = atoiOr(value, -1)
+		case "cpu MHz":
+			mhz, err := strconv.ParseFloat(value, 64)
+			if err == nil {
+				current.CurFreq = uint64(mhz * 1e6)
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return err
+	}
+
+	// sysfs also has offline CPUs, and the topology on every architecture
+	cpuDir := s.sysd() + "/devices/system/cpu"
+	onlineList := readFileLine(cpuDir + "/online")
+	online, err := parseCpuList(onlineList)
+	haveOnline := onlineList != "" && err == nil
+	entries, _ := ioutil.ReadDir(cpuDir)
+	for _, entry := range entries {
+		if !strings.HasPrefix(entry.Name(), "cpu") {
+			continue
+		}
+		id, err := strconv.Atoi(entry.Name()[3:])
+		if err != nil {
+			continue
+		}
+		c := cpu(id)
+		dir := cpuDir + "/" + entry.Name()
+
+		if haveOnline {
+			c.Online = containsInt(online, id)
+		} else if state := readFileLine(dir + "/online"); state != "" {
+			c.Online = state == "1"
+		}
+
+		if socket := readFileLine(dir + "/topology/physical_package_id"); socket != "" {
+			c.SocketId = atoiOr(socket, -1)
+		}
+		if core := readFileLine(dir + "/topology/core_id"); core != "" {
+			c.CoreId = atoiOr(core, -1)
+		}
+		siblings, err := parseCpuList(readFileLine(dir + "/topology/thread_siblings_list"))
+		if err == nil {
+			for i, sibling := range siblings {
+				if sibling == id {
+					c.ThreadId = i
+				}
+			}
+		}
+
+		// Frequencies are in kHz
+		if freq := readFileLine(dir + "/cpufreq/scaling_cur_freq"); freq != "" {
+			c.CurFreq = ReadUint(freq) * 1000
+		}
+		c.MinFreq = ReadUint(readFileLine(dir+"/cpufreq/cpuinfo_min_freq")) * 1000
+		c.MaxFreq = ReadUint(readFileLine(dir+"/cpufreq/cpuinfo_max_freq")) * 1000
+		c.Governor = readFileLine(dir + "/cpufreq/scaling_governor")
+	}
+
+	ids := make([]int, 0, len(cpus))
+	for id := range cpus {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+
+	self.List = make([]LogicalCpu, len(ids))
+	for i, id := range ids {
+		self.List[i] = *cpus[id]
+	}
+	return nil
+}
+
+func atoiOr(val string, fallback int) int {
+	result, err := strconv.Atoi(val)
+	if err != nil {
+		return fallback
+	}
+	return result
+}
+
+func containsInt(list []int, value int) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
 func (self *NetProtoV6Stats) Get() error {
-	return readFile(Procd+"/net/snmp6", func(line string) bool {
+	return self.get(defaultSigar)
+}
+
+func (self *NetProtoV6Stats) get(s *LinuxSigar) error {
+	return readFile(s.procd()+"/net/snmp6", func(line string) bool {
 		fields := strings.Fields(line)
 
 		// Lines should be key/value pairs separated by whitespace, ignore other lines
@@ -197,13 +985,17 @@ func readField(positions map[string]int, fields []string, field string) uint64 {
 }
 
 func (self *NetProtoV4Stats) Get() error {
+	return self.get(defaultSigar)
+}
+
+func (self *NetProtoV4Stats) get(s *LinuxSigar) error {
 	// Each line starts with a header that describes the values, e.g.:
 	// Udp: InDatagrams NoPorts InErrors OutDatagrams RcvbufErrors SndbufErrors
 	// This map keeps track of the names of each position for each protocol. Reload
 	// it each time we parse.
 	protocols := make(map[string]map[string]int)
 
-	return readFile(Procd+"/net/snmp", func(line string) bool {
+	return readFile(s.procd()+"/net/snmp", func(line string) bool {
 		fields := strings.Fields(line)
 		if len(fields) < 2 {
 			return true
@@ -273,6 +1065,10 @@ func (self *NetProtoV4Stats) Get() error {
 }
 
 func (self *NetIfaceList) Get() error {
+	return self.get(defaultSigar)
+}
+
+func (self *NetIfaceList) get(s *LinuxSigar) error {
 	capacity := len(self.List)
 	if capacity == 0 {
 		capacity = 10
@@ -280,7 +1076,7 @@ func (self *NetIfaceList) Get() error {
 	ifaceList := make([]NetIface, 0, capacity)
 
 	// Interface metrics come from `/proc/net/dev`
-	err := readFile(Procd+"/net/dev", func(line string) bool {
+	err := readFile(s.procd()+"/net/dev", func(line string) bool {
 		fields := strings.Fields(strings.TrimLeft(line, " \t"))
 		if len(fields) == 0 {
 			return true
@@ -325,9 +1121,9 @@ func (self *NetIfaceList) Get() error {
 	// Try to get MTU, MAC address and physical link status
 	// This will only work on 2.6 kernels and above - see https://www.kernel.org/doc/Documentation/ABI/testing/sysfs-class-net
 	for i := range ifaceList {
-		mtuFile := fmt.Sprintf("%v/class/net/%v/mtu", Sysd, ifaceList[i].Name)
-		macFile := fmt.Sprintf("%v/class/net/%v/address", Sysd, ifaceList[i].Name)
-		linkStatFile := fmt.Sprintf("%v/class/net/%v/carrier", Sysd, ifaceList[i].Name)
+		mtuFile := fmt.Sprintf("%v/class/net/%v/mtu", s.sysd(), ifaceList[i].Name)
+		macFile := fmt.Sprintf("%v/class/net/%v/address", s.sysd(), ifaceList[i].Name)
+		linkStatFile := fmt.Sprintf("%v/class/net/%v/carrier", s.sysd(), ifaceList[i].Name)
 
 		ifaceList[i].MTU = ReadUint(readFileLine(mtuFile))
 		ifaceList[i].Mac = readFileLine(macFile)
@@ -367,11 +1163,11 @@ func extractInode(linkName string) (uint64, error) {
 }
 
 // Map pid to process name
-func buildPidMap(pids []int) map[int]string {
+func (s *LinuxSigar) buildPidMap(pids []int) map[int]string {
 	pidMap := make(map[int]string)
 	procState := ProcState{}
 	for _, pid := range pids {
-		err := procState.Get(pid)
+		err := procState.get(s, pid)
 		if err == nil {
 			pidMap[pid] = procState.Name
 		}
@@ -379,12 +1175,12 @@ func buildPidMap(pids []int) map[int]string {
 	return pidMap
 }
 
-func populatePidProcessName(netConnsPtr *[]NetConn) {
+func (s *LinuxSigar) populatePidProcessName(ctx context.Context, netConnsPtrs ...*[]NetConn) error {
 	// Gather the list of pids
 	pids := ProcList{}
-	err := pids.Get()
+	err := pids.get(s)
 	if err != nil {
-		return
+		return nil
 	}
 
 	// For each process, read the links under the `fd` dir. If the linkName matches a specific form,
@@ -393,100 +1189,367 @@ func populatePidProcessName(netConnsPtr *[]NetConn) {
 	// Any discovered inodes are paired with the pid to match below
 	inodeCache := make(map[uint64]int)
 	for _, pid := range pids.List {
-		// Open the directory and list the links, ignoring all errors. We won't be able to read
-		// non-owned directories unless we're root, so much of the time the open of `fd` will fail.
-		fdDir := procFileName(pid, "fd")
-		dir, err := os.Open(fdDir)
+		fdDir := s.procFileName(pid, "fd")
+		inodes, err := blockingCalls.do(ctx, "readlink", fdDir, pid, func() (interface{}, error) {
+			return readSocketInodes(fdDir), nil
+		})
+		if err != nil {
+			return err
+		}
+		for _, inode := range inodes.([]uint64) {
+			inodeCache[inode] = pid
+		}
+	}
+
+	// Gather pid process names
+	pidMap := s.buildPidMap(pids.List)
+
+	// Match netConn inodes with pids
+	for _, netConnsPtr := range netConnsPtrs {
+		netConns := *netConnsPtr
+		for i, _ := range netConns {
+			inode := netConns[i].Inode
+			if inode != 0 {
+				pid := inodeCache[inode]
+				netConns[i].Pid = pid
+				netConns[i].ProcessName = pidMap[pid]
+			}
+		}
+	}
+	return nil
+}
+
+// List the socket inodes held open in a /proc/<pid>/fd directory
+func readSocketInodes(fdDir string) []uint64 {
+	var inodes []uint64
+
+	// Open the directory and list the links, ignoring all errors. We won't be able to read
+	// non-owned directories unless we're root, so much of the time the open of `fd` will fail.
+	dir, err := os.Open(fdDir)
+	if err != nil {
+		return inodes
+	}
+	defer dir.Close()
+
+	names, err := dir.Readdirnames(readAllDirnames)
+	if err != nil {
+		return inodes
+	}
+	for _, name := range names {
+		linkName, err := os.Readlink(filepath.Join(fdDir, name))
 		if err == nil {
-			names, err := dir.Readdirnames(readAllDirnames)
+			inode, err := extractInode(linkName)
 			if err == nil {
-				for _, name := range names {
-					linkName, err := os.Readlink(filepath.Join(fdDir, name))
-					if err == nil {
-						inode, err := extractInode(linkName)
-						if err == nil {
-							inodeCache[inode] = pid
-						}
-					}
+				inodes = append(inodes, inode)
+			}
+		}
+	}
+	return inodes
+}
+
+func (self *DeletedOpenFiles) Get() error {
+	return self.get(defaultSigar)
+}
+
+func (self *DeletedOpenFiles) get(s *LinuxSigar) error {
+	return self.getContext(context.Background(), s)
+}
+
+// Walks the fd links of every process like populatePidProcessName, so without
+// root only the files of our own processes are found.
+func (self *DeletedOpenFiles) getContext(ctx context.Context, s *LinuxSigar) error {
+	pids := ProcList{}
+	err := pids.get(s)
+	if err != nil {
+		return err
+	}
+
+	// Without the mounts the files are still worth reporting
+	fslist := FileSystemList{}
+	fslist.get(s)
+
+	files := make([]DeletedOpenFile, 0)
+	for _, pid := range pids.List {
+		fdDir := s.procFileName(pid, "fd")
+		val, err := blockingCalls.do(ctx, "readlink", fdDir, pid, func() (interface{}, error) {
+			return readDeletedFds(fdDir), nil
+		})
+		if err != nil {
+			self.List = files
+			return err
+		}
+		deleted := val.([]DeletedOpenFile)
+		if len(deleted) == 0 {
+			continue
+		}
+
+		procState := ProcState{}
+		if IsProcessGone(procState.get(s, pid)) {
+			// The files were closed when it exited
+			continue
+		}
+
+		var pidMounts *FileSystemList
+		for i := range deleted {
+			deleted[i].Pid = pid
+			deleted[i].ProcessName = procState.Name
+
+			mount, ok := mountOfFile(fslist.List, deleted[i])
+			if !ok {
+				// Mounted only in the namespace of the process, e.g. in a container
+				if pidMounts == nil {
+					pidMounts = &FileSystemList{}
+					pidMounts.getPid(s, pid)
 				}
+				mount, ok = mountOfFile(pidMounts.List, deleted[i])
+			}
+			if ok {
+				deleted[i].DirName = mount.DirName
 			}
-			_ = dir.Close()
 		}
+		files = append(files, deleted...)
 	}
 
-	// Gather pid process names
-	pidMap := buildPidMap(pids.List)
+	sort.Sort(deletedOpenFilesByFd(files))
+	self.List = files
+	return nil
+}
 
-	// Match netConn inodes with pids
-	netConns := *netConnsPtr
-	for i, _ := range netConns {
-		inode := netConns[i].Inode
-		if inode != 0 {
-			pid := inodeCache[inode]
-			netConns[i].Pid = pid
-			netConns[i].ProcessName = pidMap[pid]
+type deletedOpenFilesByFd []DeletedOpenFile
+
+func (self deletedOpenFilesByFd) Len() int      { return len(self) }
+func (self deletedOpenFilesByFd) Swap(i, j int) { self[i], self[j] = self[j], self[i] }
+func (self deletedOpenFilesByFd) Less(i, j int) bool {
+	if self[i].Pid != self[j].Pid {
+		return self[i].Pid < self[j].Pid
+	}
+	return self[i].Fd < self[j].Fd
+}
+
+const deletedSuffix = " (deleted)"
+
+// List the deleted regular files held open in a /proc/<pid>/fd directory,
+// ignoring all errors like readSocketInodes
+func readDeletedFds(fdDir string) []DeletedOpenFile {
+	var files []DeletedOpenFile
+
+	dir, err := os.Open(fdDir)
+	if err != nil {
+		return files
+	}
+	defer dir.Close()
+
+	names, err := dir.Readdirnames(readAllDirnames)
+	if err != nil {
+		return files
+	}
+	for _, name := range names {
+		fd, err := strconv.Atoi(name)
+		if err != nil {
+			continue
 		}
+		link, err := os.Readlink(filepath.Join(fdDir, name))
+		if err != nil || !strings.HasSuffix(link, deletedSuffix) {
+			continue
+		}
+		path := strings.TrimSuffix(link, deletedSuffix)
+
+		// Anonymous files like memfds were never on a filesystem
+		if !strings.HasPrefix(path, "/") || strings.HasPrefix(path, "/memfd:") {
+			continue
+		}
+
+		// Stat follows the fd to the deleted file itself
+		stat := syscall.Stat_t{}
+		err = syscall.Stat(filepath.Join(fdDir, name), &stat)
+		if err != nil || stat.Mode&syscall.S_IFMT != syscall.S_IFREG {
+			continue
+		}
+
+		major, minor := devNumbers(uint64(stat.Dev))
+		files = append(files, DeletedOpenFile{
+			Fd:    fd,
+			Path:  path,
+			Size:  uint64(stat.Size),
+			Inode: uint64(stat.Ino),
+			Major: major,
+			Minor: minor,
+		})
 	}
+	return files
+}
+
+// The mount of the device holding file. Of several mounts of the device, e.g.
+// bind mounts, the deepest one above the path of the file is picked.
+func mountOfFile(mounts []FileSystem, file DeletedOpenFile) (FileSystem, bool) {
+	found := -1
+	foundAbove := false
+	for i, fs := range mounts {
+		if fs.Major != file.Major || fs.Minor != file.Minor {
+			continue
+		}
+		above := fs.DirName == "/" || file.Path == fs.DirName || strings.HasPrefix(file.Path, fs.DirName+"/")
+		if found == -1 || (above && (!foundAbove || len(fs.DirName) > len(mounts[found].DirName))) {
+			found, foundAbove = i, above
+		}
+	}
+	if found == -1 {
+		return FileSystem{}, false
+	}
+	return mounts[found], true
+}
+
+// Split a dev_t into its major and minor numbers, like glibc's major(3)
+func devNumbers(dev uint64) (uint64, uint64) {
+	major := (dev>>8)&0xfff | (dev>>32)&0xfffff000
+	minor := dev&0xff | (dev>>12)&0xffffff00
+	return major, minor
 }
 
 func (self *NetTcpConnList) Get() error {
-	list, err := readConnList(Procd+"/net/tcp", ConnProtoTcp, 4, 17)
+	return self.get(defaultSigar)
+}
+
+func (self *NetTcpConnList) get(s *LinuxSigar) error {
+	return self.getContext(context.Background(), s)
+}
+
+func (self *NetTcpConnList) getContext(ctx context.Context, s *LinuxSigar) error {
+	list, err := readConnList(s.procd()+"/net/tcp", ConnProtoTcp, 4, 17)
 	if err != nil {
 		return err
 	}
 	self.List = list
-	populatePidProcessName(&self.List)
-	return nil
+	return s.populatePidProcessName(ctx, &self.List)
 }
 
 func (self *NetUdpConnList) Get() error {
-	list, err := readConnList(Procd+"/net/udp", ConnProtoUdp, 4, 13)
+	return self.get(defaultSigar)
+}
+
+func (self *NetUdpConnList) get(s *LinuxSigar) error {
+	return self.getContext(context.Background(), s)
+}
+
+func (self *NetUdpConnList) getContext(ctx context.Context, s *LinuxSigar) error {
+	list, err := readConnList(s.procd()+"/net/udp", ConnProtoUdp, 4, 13)
 	if err != nil {
 		return err
 	}
 	self.List = list
-	populatePidProcessName(&self.List)
-	return nil
+	return s.populatePidProcessName(ctx, &self.List)
 }
 
 func (self *NetRawConnList) Get() error {
-	list, err := readConnList(Procd+"/net/raw", ConnProtoRaw, 4, 13)
+	return self.get(defaultSigar)
+}
+
+func (self *NetRawConnList) get(s *LinuxSigar) error {
+	return self.getContext(context.Background(), s)
+}
+
+func (self *NetRawConnList) getContext(ctx context.Context, s *LinuxSigar) error {
+	list, err := readConnList(s.procd()+"/net/raw", ConnProtoRaw, 4, 13)
 	if err != nil {
 		return err
 	}
 	self.List = list
-	populatePidProcessName(&self.List)
-	return nil
+	return s.populatePidProcessName(ctx, &self.List)
 }
 
 func (self *NetTcpV6ConnList) Get() error {
-	list, err := readConnList(Procd+"/net/tcp6", ConnProtoTcp, 16, 17)
+	return self.get(defaultSigar)
+}
+
+func (self *NetTcpV6ConnList) get(s *LinuxSigar) error {
+	return self.getContext(context.Background(), s)
+}
+
+func (self *NetTcpV6ConnList) getContext(ctx context.Context, s *LinuxSigar) error {
+	list, err := readConnList(s.procd()+"/net/tcp6", ConnProtoTcp, 16, 17)
 	if err != nil {
 		return err
 	}
 	self.List = list
-	populatePidProcessName(&self.List)
-	return nil
+	return s.populatePidProcessName(ctx, &self.List)
 }
 
 func (self *NetUdpV6ConnList) Get() error {
-	list, err := readConnList(Procd+"/net/udp6", ConnProtoUdp, 16, 13)
+	return self.get(defaultSigar)
+}
+
+func (self *NetUdpV6ConnList) get(s *LinuxSigar) error {
+	return self.getContext(context.Background(), s)
+}
+
+func (self *NetUdpV6ConnList) getContext(ctx context.Context, s *LinuxSigar) error {
+	list, err := readConnList(s.procd()+"/net/udp6", ConnProtoUdp, 16, 13)
 	if err != nil {
 		return err
 	}
 	self.List = list
-	populatePidProcessName(&self.List)
-	return nil
+	return s.populatePidProcessName(ctx, &self.List)
 }
 
 func (self *NetRawV6ConnList) Get() error {
-	list, err := readConnList(Procd+"/net/raw6", ConnProtoRaw, 16, 13)
+	return self.get(defaultSigar)
+}
+
+func (self *NetRawV6ConnList) get(s *LinuxSigar) error {
+	return self.getContext(context.Background(), s)
+}
+
+func (self *NetRawV6ConnList) getContext(ctx context.Context, s *LinuxSigar) error {
+	list, err := readConnList(s.procd()+"/net/raw6", ConnProtoRaw, 16, 13)
 	if err != nil {
 		return err
 	}
 	self.List = list
-	populatePidProcessName(&self.List)
-	return nil
+	return s.populatePidProcessName(ctx, &self.List)
+}
+
+func (self *NetConnList) Get() error {
+	return self.get(defaultSigar)
+}
+
+func (self *NetConnList) get(s *LinuxSigar) error {
+	return self.getContext(context.Background(), s)
+}
+
+func (self *NetConnList) getContext(ctx context.Context, s *LinuxSigar) error {
+	self.V4 = self.V4[:0]
+	self.V6 = self.V6[:0]
+	for _, table := range procNetConnFiles {
+		list, err := readConnList(s.procd()+"/"+table.name, table.proto, table.ipSizeBytes, table.numFields)
+		if table.ipSizeBytes == 16 {
+			if err != nil {
+				// No IPv6
+				continue
+			}
+			self.V6 = append(self.V6, list...)
+		} else {
+			if err != nil {
+				return err
+			}
+			self.V4 = append(self.V4, list...)
+		}
+	}
+	return s.populatePidProcessName(ctx, &self.V4, &self.V6)
+}
+
+// The /proc/net files listing sockets, and how to parse each
+var procNetConnFiles = []struct {
+	name        string
+	proto       NetConnProto
+	ipSizeBytes int
+	numFields   int
+}{
+	{"net/tcp", ConnProtoTcp, 4, 17},
+	{"net/udp", ConnProtoUdp, 4, 13},
+	{"net/raw", ConnProtoRaw, 4, 13},
+	{"net/tcp6", ConnProtoTcp, 16, 17},
+	{"net/udp6", ConnProtoUdp, 16, 13},
+	{"net/raw6", ConnProtoRaw, 16, 13},
 }
 
 /* Reads the format of the /proc/net/<proto> files, which have 2 header lines and a
@@ -582,59 +1645,513 @@ func ReadConnIp(field string, lenBytes int) (net.IP, uint64, error) {
 	return net.IP(ip), uint64(port), nil
 }
 
+// Sizes in statfs are in fragments, as for statvfs
+func statfsFragmentSize(stat *syscall.Statfs_t) uint64 {
+	if stat.Frsize > 0 {
+		return uint64(stat.Frsize)
+	}
+	return uint64(stat.Bsize)
+}
+
+// The ST_* flags of statfs match the MS_* mount flags
+func setStatfsFlags(usage *FileSystemUsage, stat *syscall.Statfs_t) {
+	usage.MaxNameLength = uint64(stat.Namelen)
+	usage.ReadOnly = stat.Flags&syscall.MS_RDONLY != 0
+	usage.NoExec = stat.Flags&syscall.MS_NOEXEC != 0
+	usage.NoSuid = stat.Flags&syscall.MS_NOSUID != 0
+}
+
 func (self *FileSystemList) Get() error {
-	capacity := len(self.List)
-	if capacity == 0 {
-		capacity = 10
+	return self.get(defaultSigar)
+}
+
+func (self *FileSystemList) get(s *LinuxSigar) error {
+	return self.getContext(context.Background(), s)
+}
+
+func (self *FileSystemList) getContext(ctx context.Context, s *LinuxSigar) error {
+	mountinfo := s.procd() + "/self/mountinfo"
+	fslist, err := blockingCalls.do(ctx, "read", mountinfo, 0, func() (interface{}, error) {
+		list, err := readMountInfo(mountinfo)
+		if os.IsNotExist(err) {
+			// Kernels before 2.6.26
+			return readFileSystemList(s.etcd() + "/mtab")
+		}
+		return list, err
+	})
+	if err != nil {
+		return err
+	}
+	self.List = fslist.([]FileSystem)
+	return nil
+}
+
+// GetPid lists the mounts in the mount namespace of a process
+func (self *FileSystemList) GetPid(pid int) error {
+	return self.getPid(defaultSigar, pid)
+}
+
+func (self *FileSystemList) getPid(s *LinuxSigar, pid int) error {
+	mountinfo := s.procFileName(pid, "mountinfo")
+	list, err := readMountInfo(mountinfo)
+	if os.IsNotExist(err) {
+		return readError(mountinfo, err, true)
+	}
+	if err != nil {
+		return err
+	}
+	self.List = list
+	return nil
+}
+
+// Parse lines like
+// 36 35 98:0 /mnt1 /mnt2 rw,noatime master:1 - ext3 /dev/root rw,errors=continue
+func readMountInfo(mountinfo string) ([]FileSystem, error) {
+	fslist := make([]FileSystem, 0, 10)
+
+	var parseErr error
+	err := readFile(mountinfo, func(line string) bool {
+		fields := strings.Fields(line)
+
+		// Optional fields end with a "-"
+		separator := -1
+		for i := 6; i < len(fields); i++ {
+			if fields[i] == "-" {
+				separator = i
+				break
+			}
+		}
+		if separator < 0 || len(fields) < separator+3 {
+			return true
+		}
+
+		fs := FileSystem{}
+		var err error
+		if fs.MountId, err = strconv.Atoi(fields[0]); err != nil {
+			parseErr = parseError(mountinfo, "MountId", err)
+			return false
+		}
+		if fs.ParentId, err = strconv.Atoi(fields[1]); err != nil {
+			parseErr = parseError(mountinfo, "ParentId", err)
+			return false
+		}
+		dev := strings.SplitN(fields[2], ":", 2)
+		if len(dev) == 2 {
+			fs.Major = ReadUint(dev[0])
+			fs.Minor = ReadUint(dev[1])
+		}
+		fs.Root = unescapeOctal(fields[3])
+		fs.DirName = unescapeOctal(fields[4])
+		fs.Options = fields[5]
+		fs.Propagation = strings.Join(fields[6:separator], " ")
+		fs.SysTypeName = fields[separator+1]
+		fs.DevName = unescapeOctal(fields[separator+2])
+		if len(fields) > separator+3 {
+			fs.SuperOptions = fields[separator+3]
+		}
+		fs.Flags = mountFlags(fs.Options)
+		fs.Class = classifyFileSystem(fs.SysTypeName)
+		fs.TypeName = fs.Class.String()
+
+		fslist = append(fslist, fs)
+		return true
+	})
+	if err != nil {
+		return nil, err
 	}
-	fslist := make([]FileSystem, 0, capacity)
+	return fslist, parseErr
+}
 
-	err := readFile("/etc/mtab", func(line string) bool {
+func readFileSystemList(mtab string) ([]FileSystem, error) {
+	fslist := make([]FileSystem, 0, 10)
+
+	err := readFile(mtab, func(line string) bool {
 		fields := strings.Fields(line)
+		if len(fields) < 4 {
+			return true
+		}
 
 		fs := FileSystem{}
-		fs.DevName = fields[0]
-		fs.DirName = fields[1]
+		fs.DevName = unescapeOctal(fields[0])
+		fs.DirName = unescapeOctal(fields[1])
 		fs.SysTypeName = fields[2]
 		fs.Options = fields[3]
+		fs.Flags = mountFlags(fs.Options)
+		fs.Class = classifyFileSystem(fs.SysTypeName)
+		fs.TypeName = fs.Class.String()
 
 		fslist = append(fslist, fs)
 
 		return true
 	})
 
-	self.List = fslist
+	return fslist, err
+}
 
-	return err
+var mountOptionFlags = map[string]uint32{
+	"ro":         syscall.MS_RDONLY,
+	"nosuid":     syscall.MS_NOSUID,
+	"nodev":      syscall.MS_NODEV,
+	"noexec":     syscall.MS_NOEXEC,
+	"sync":       syscall.MS_SYNCHRONOUS,
+	"mand":       syscall.MS_MANDLOCK,
+	"dirsync":    syscall.MS_DIRSYNC,
+	"noatime":    syscall.MS_NOATIME,
+	"nodiratime": syscall.MS_NODIRATIME,
+	"relatime":   syscall.MS_RELATIME,
 }
 
-func (self *DiskList) Get() error {
-	/* List all the partitions, and check the major/minor device ID
-	   to find which are devices vs. partitions (ex. sda v. sda1) */
-	devices := make(map[string]bool)
-	diskList := make(map[string]DiskIo)
-	err := readFile(Procd+"/partitions", func(line string) bool {
+func mountFlags(options string) uint32 {
+	var flags uint32
+	for _, option := range strings.Split(options, ",") {
+		flags |= mountOptionFlags[option]
+	}
+	return flags
+}
+
+var networkFileSystems = map[string]bool{
+	"9p": true, "afs": true, "beegfs": true, "ceph": true, "cifs": true,
+	"fuse.glusterfs": true, "fuse.s3fs": true, "fuse.sshfs": true, "glusterfs": true,
+	"gpfs": true, "lustre": true, "ncpfs": true, "nfs": true, "nfs4": true,
+	"smb3": true, "smbfs": true,
+}
+
+var pseudoFileSystems = map[string]bool{
+	"autofs": true, "binfmt_misc": true, "bpf": true, "cgroup": true, "cgroup2": true,
+	"configfs": true, "debugfs": true, "devpts": true, "devtmpfs": true, "efivarfs": true,
+	"fusectl": true, "hugetlbfs": true, "mqueue": true, "nsfs": true, "proc": true,
+	"pstore": true, "ramfs": true, "rpc_pipefs": true, "securityfs": true,
+	"selinuxfs": true, "sysfs": true, "tmpfs": true, "tracefs": true,
+}
+
+func classifyFileSystem(fsType string) FileSystemClass {
+	switch {
+	case networkFileSystems[fsType]:
+		return FileSystemNetwork
+	case pseudoFileSystems[fsType]:
+		return FileSystemPseudo
+	case fsType == "overlay" || fsType == "aufs" || fsType == "fuse.fuse-overlayfs":
+		return FileSystemOverlay
+	}
+	return FileSystemLocal
+}
+
+func (self *BlockDeviceList) Get() error {
+	return self.get(defaultSigar)
+}
+
+func (self *BlockDeviceList) get(s *LinuxSigar) error {
+	blockDir := s.sysd() + "/block"
+	entries, err := ioutil.ReadDir(blockDir)
+	if err != nil {
+		return readError(blockDir, err, false)
+	}
+
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	sort.Strings(names)
+
+	list := make([]BlockDevice, 0, len(names))
+	for _, name := range names {
+		dir := blockDir + "/" + name
+		disk := readBlockDevice(dir, name)
+		disk.LogicalSectorSize = ReadUint(readFileLine(dir + "/queue/logical_block_size"))
+		disk.PhysicalSectorSize = ReadUint(readFileLine(dir + "/queue/physical_block_size"))
+		disk.Rotational = readFileLine(dir+"/queue/rotational") == "1"
+		disk.Removable = readFileLine(dir+"/removable") == "1"
+		disk.Hidden = readFileLine(dir+"/hidden") == "1"
+		disk.Scheduler = parseScheduler(readFileLine(dir + "/queue/scheduler"))
+
+		disk.Model = readFileLine(dir + "/device/model")
+		disk.Serial = readFileLine(dir + "/device/serial")
+		if disk.Serial == "" {
+			disk.Serial = readFileLine(dir + "/serial") // virtio
+		}
+
+		slaves, _ := ioutil.ReadDir(dir + "/slaves")
+		for _, slave := range slaves {
+			disk.Slaves = append(disk.Slaves, slave.Name())
+		}
+
+		if mapperName := readFileLine(dir + "/dm/name"); mapperName != "" {
+			disk.MapperName = mapperName
+			if strings.HasPrefix(readFileLine(dir+"/dm/uuid"), "LVM-") {
+				disk.VolumeGroup, disk.LogicalVolume = splitLvmName(mapperName)
+			}
+		}
+		list = append(list, disk)
+
+		// Partitions are the subdirectories with a partition file
+		subdirs, _ := ioutil.ReadDir(dir)
+		for _, subdir := range subdirs {
+			partDir := dir + "/" + subdir.Name()
+			if _, err := os.Stat(partDir + "/partition"); err != nil {
+				continue
+			}
+			part := readBlockDevice(partDir, subdir.Name())
+			part.Parent = name
+			part.LogicalSectorSize = disk.LogicalSectorSize
+			part.PhysicalSectorSize = disk.PhysicalSectorSize
+			part.Rotational = disk.Rotational
+			part.Removable = disk.Removable
+			part.Scheduler = disk.Scheduler
+			part.Model = disk.Model
+			part.Serial = disk.Serial
+			list = append(list, part)
+		}
+	}
+
+	self.List = list
+	return nil
+}
+
+// Attributes shared by disks and partitions
+func readBlockDevice(dir, name string) BlockDevice {
+	device := BlockDevice{Name: name}
+	dev := strings.SplitN(readFileLine(dir+"/dev"), ":", 2)
+	if len(dev) == 2 {
+		device.Major = ReadUint(dev[0])
+		device.Minor = ReadUint(dev[1])
+	}
+	device.Size = ReadUint(readFileLine(dir+"/size")) * 512 // Always in 512 byte sectors
+	device.ReadOnly = readFileLine(dir+"/ro") == "1"
+	return device
+}
+
+// The active scheduler is in brackets, e.g. "none [mq-deadline] kyber"
+func parseScheduler(schedulers string) string {
+	start := strings.Index(schedulers, "[")
+	end := strings.Index(schedulers, "]")
+	if start >= 0 && end > start {
+		return schedulers[start+1 : end]
+	}
+	return schedulers
+}
+
+// LVM names dm devices "<vg>-<lv>", doubling any dash within the names
+func splitLvmName(name string) (string, string) {
+	for i := 0; i < len(name); i++ {
+		if name[i] != '-' {
+			continue
+		}
+		if i+1 < len(name) && name[i+1] == '-' {
+			i++
+			continue
+		}
+		unescape := func(str string) string {
+			return strings.Replace(str, "--", "-", -1)
+		}
+		return unescape(name[:i]), unescape(name[i+1:])
+	}
+	return name, ""
+}
+
+func (self *MdArrayList) Get() error {
+	return self.get(defaultSigar)
+}
+
+// Returns an ErrNotSupported *Error where the md driver isn't loaded
+func (self *MdArrayList) get(s *LinuxSigar) error {
+	mdstatFile := s.procd() + "/mdstat"
+	contents, err := ioutil.ReadFile(mdstatFile)
+	if os.IsNotExist(err) {
+		return &Error{Kind: ErrNotSupported, Path: mdstatFile, Err: err}
+	}
+	if err != nil {
+		return readError(mdstatFile, err, false)
+	}
+
+	list := []MdArray{}
+	var array *MdArray
+	for _, line := range strings.Split(string(contents), "\n") {
 		fields := strings.Fields(line)
-		if len(fields) < 4 {
-			return true
+		switch {
+		case len(fields) >= 3 && fields[1] == ":" && strings.HasPrefix(fields[0], "md"):
+			list = append(list, parseMdArray(fields))
+			array = &list[len(list)-1]
+		case array == nil || len(fields) == 0:
+			continue
+		case strings.Contains(line, "blocks"):
+			if err := parseMdStatus(array, line); err != nil {
+				return parseError(mdstatFile, array.Name, err)
+			}
+		default:
+			parseMdSync(array, line)
 		}
-		majorDevId, err := strtoull(fields[0])
-		if err != nil {
-			return true
+	}
+
+	// sysfs has the precise array and member states
+	for i := range list {
+		readMdSysfs(&list[i], s.sysd()+"/block/"+list[i].Name+"/md")
+	}
+
+	self.List = list
+	return nil
+}
+
+// e.g. "md1 : active raid5 sdd1[3](F) sdc1[2] sdb2[1] sda2[0]"
+func parseMdArray(fields []string) MdArray {
+	array := MdArray{Name: fields[0], State: fields[2], Active: fields[2] == "active", SyncAction: "idle"}
+
+	members := fields[3:]
+	if len(members) > 0 && strings.HasPrefix(members[0], "(") {
+		members = members[1:] // (auto-read-only)
+	}
+	if array.Active && len(members) > 0 && !strings.Contains(members[0], "[") {
+		array.Level = members[0]
+		members = members[1:]
+	}
+
+	for _, member := range members {
+		start := strings.Index(member, "[")
+		end := strings.Index(member, "]")
+		if start < 0 || end < start {
+			continue
 		}
-		minorDevId, err := strtoull(fields[1])
+		slot, err := strconv.Atoi(member[start+1 : end])
 		if err != nil {
+			continue
+		}
+
+		m := MdMember{Name: member[:start], Slot: slot, State: "in_sync"}
+		flags := member[end:]
+		switch {
+		case strings.Contains(flags, "(F)"):
+			m.State, m.Slot = "faulty", -1
+			array.Failed++
+		case strings.Contains(flags, "(S)"):
+			m.State, m.Slot = "spare", -1
+			array.Spares++
+		}
+		array.Members = append(array.Members, m)
+	}
+	return array
+}
+
+// e.g. "2095104 blocks super 1.2 level 5, 512k chunk, algorithm 2 [3/2] [UU_]"
+func parseMdStatus(array *MdArray, line string) error {
+	fields := strings.Fields(line)
+	blocks, err := strtoull(fields[0])
+	if err != nil {
+		return err
+	}
+	array.Size = blocks * 1024
+
+	for _, field := range fields {
+		if !strings.HasPrefix(field, "[") || !strings.Contains(field, "/") {
+			continue
+		}
+		disks := strings.SplitN(strings.Trim(field, "[]"), "/", 2)
+		if array.RaidDisks, err = strconv.Atoi(disks[0]); err != nil {
+			return err
+		}
+		if array.ActiveDisks, err = strconv.Atoi(disks[1]); err != nil {
+			return err
+		}
+		array.Degraded = array.RaidDisks - array.ActiveDisks
+	}
+	return nil
+}
+
+var mdSyncMatcher = regexp.MustCompile(`(resync|recovery|check|repair|reshape)\s*=\s*[\d.]+%\s*\((\d+)/(\d+)\)\s*finish=([\d.]+)min\s*speed=(\d+)K/sec`)
+
+// e.g. "[==>....]  recovery = 12.6% (132480/1047552) finish=0.5min speed=26496K/sec"
+func parseMdSync(array *MdArray, line string) {
+	match := mdSyncMatcher.FindStringSubmatch(line)
+	if match == nil {
+		return
+	}
+
+	array.SyncAction = match[1]
+	if array.SyncAction == "recovery" {
+		array.SyncAction = "recover" // As in sysfs
+	}
+	done, total := ReadUint(match[2]), ReadUint(match[3])
+	if total > 0 {
+		array.SyncCompleted = float64(done) / float64(total)
+	}
+	minutes, _ := strconv.ParseFloat(match[4], 64)
+	array.SyncEta = time.Duration(minutes * float64(time.Minute))
+	array.SyncSpeed = ReadUint(match[5]) * 1024
+}
+
+func readMdSysfs(array *MdArray, dir string) {
+	if state := readFileLine(dir + "/array_state"); state != "" {
+		array.State = state
+	}
+	if level := readFileLine(dir + "/level"); level != "" {
+		array.Level = level
+	}
+	if degraded := readFileLine(dir + "/degraded"); degraded != "" {
+		array.Degraded = atoiOr(degraded, array.Degraded)
+	}
+	if action := readFileLine(dir + "/sync_action"); action != "" {
+		array.SyncAction = action
+	}
+
+	for i := range array.Members {
+		member := &array.Members[i]
+		memberDir := dir + "/dev-" + member.Name
+		if state := readFileLine(memberDir + "/state"); state != "" {
+			member.State = state
+		}
+		if slot := readFileLine(memberDir + "/slot"); slot != "" {
+			member.Slot = atoiOr(slot, -1) // "none" for spares
+		}
+	}
+
+	// Recount from the final states, which may differ from the mdstat flags
+	array.Failed, array.Spares = 0, 0
+	for _, member := range array.Members {
+		switch {
+		case hasMdState(member.State, "faulty"):
+			array.Failed++
+		case hasMdState(member.State, "spare"):
+			array.Spares++
+		}
+	}
+}
+
+// Whether a comma separated member state, e.g. "faulty,write_error", has flag
+func hasMdState(state, flag string) bool {
+	for _, s := range strings.Split(state, ",") {
+		if s == flag {
 			return true
 		}
-		if isNotPartition(majorDevId, minorDevId) {
-			devices[fields[3]] = true
+	}
+	return false
+}
+
+func (self *DiskList) Get() error {
+	return self.get(defaultSigar)
+}
+
+func (self *DiskList) get(s *LinuxSigar) error {
+	devices := make(map[string]bool)
+	diskList := make(map[string]DiskIo)
+
+	// Whole disks, skipping empty devices like /proc/partitions does
+	blockDevices := BlockDeviceList{}
+	err := blockDevices.get(s)
+	if err == nil && len(blockDevices.List) > 0 {
+		for _, device := range blockDevices.List {
+			if !device.IsPartition() && device.Size > 0 && !device.Hidden {
+				devices[device.Name] = true
+			}
 		}
-		return true
-	})
+	} else {
+		err = readPartitions(s, devices)
+	}
+	if err != nil {
+		return err
+	}
 
-	/* Get all device stats from /proc/diskstats and filter by
-	   devices from /proc/partitions */
-	err = readFile(Procd+"/diskstats", func(line string) bool {
+	// Get all device stats from /proc/diskstats and filter by whole disks
+	diskstatsFile := s.procd() + "/diskstats"
+	partial := &PartialError{}
+	err = readFile(diskstatsFile, func(line string) bool {
 		fields := strings.Fields(line)
 		if len(fields) < 13 {
 			return true
@@ -644,50 +2161,146 @@ func (self *DiskList) Get() error {
 			return true
 		}
 		io := DiskIo{}
-		io.ReadOps, _ = strtoull(fields[3])
-		readBytes, _ := strtoull(fields[5])
-		io.ReadBytes = readBytes * 512
-		io.ReadTimeMs, _ = strtoull(fields[6])
-		io.WriteOps, _ = strtoull(fields[7])
-		writeBytes, _ := strtoull(fields[9])
-		io.WriteBytes = writeBytes * 512
-		io.WriteTimeMs, _ = strtoull(fields[10])
-		io.IoTimeMs, _ = strtoull(fields[12])
+		var readSectors, writeSectors, discardSectors uint64
+		columns := []struct {
+			name  string
+			index int
+			val   *uint64
+		}{
+			{"ReadOps", 3, &io.ReadOps},
+			{"ReadMerged", 4, &io.ReadMerged},
+			{"ReadBytes", 5, &readSectors},
+			{"ReadTimeMs", 6, &io.ReadTimeMs},
+			{"WriteOps", 7, &io.WriteOps},
+			{"WriteMerged", 8, &io.WriteMerged},
+			{"WriteBytes", 9, &writeSectors},
+			{"WriteTimeMs", 10, &io.WriteTimeMs},
+			{"IoInProgress", 11, &io.IoInProgress},
+			{"IoTimeMs", 12, &io.IoTimeMs},
+			{"WeightedIoTimeMs", 13, &io.WeightedIoTimeMs},
+			// Discards since 4.18, flushes since 5.5
+			{"DiscardOps", 14, &io.DiscardOps},
+			{"DiscardMerged", 15, &io.DiscardMerged},
+			{"DiscardBytes", 16, &discardSectors},
+			{"DiscardTimeMs", 17, &io.DiscardTimeMs},
+			{"FlushOps", 18, &io.FlushOps},
+			{"FlushTimeMs", 19, &io.FlushTimeMs},
+		}
+		for _, column := range columns {
+			if column.index >= len(fields) {
+				break
+			}
+			val, err := strtoull(fields[column.index])
+			if err != nil {
+				partial.add(deviceName, parseError(diskstatsFile, column.name, err))
+				return true
+			}
+			*column.val = val
+		}
+		io.ReadBytes = readSectors * 512
+		io.WriteBytes = writeSectors * 512
+		io.DiscardBytes = discardSectors * 512
 		diskList[deviceName] = io
 		return true
 	})
+	if err != nil {
+		return err
+	}
 	self.List = diskList
-	return err
+	return partial.orNil()
 }
 
 func (self *ProcessList) Get() error {
+	return self.get(defaultSigar)
+}
+
+func (self *ProcessList) get(s *LinuxSigar) error {
+	return self.getContext(context.Background(), s)
+}
+
+func (self *ProcessList) getContext(ctx context.Context, s *LinuxSigar) error {
 	pids := ProcList{}
-	err := pids.Get()
+	err := pids.get(s)
 	if err != nil {
 		return err
 	}
 
+	type result struct {
+		process Process
+		err     error
+	}
+
 	processes := make([]Process, 0, len(pids.List))
+	partial := &PartialError{}
 	for _, pid := range pids.List {
-		var process Process
-
-		// Gather each composed struct, ignoring any errors.
-		_ = process.ProcState.Get(pid)
-		_ = process.ProcIo.Get(pid)
-		_ = process.ProcMem.Get(pid)
-		_ = process.ProcTime.Get(pid)
-		_ = process.ProcArgs.Get(pid)
-		_ = process.ProcExe.Get(pid)
+		// Reads of some /proc/<pid> files block while the process holds its mmap lock
+		val, err := blockingCalls.do(ctx, "read", s.procFileName(pid, ""), pid, func() (interface{}, error) {
+			var process Process
+			err := process.get(s, pid)
+			return result{process, err}, nil
+		})
+		if err != nil {
+			self.List = processes
+			return err
+		}
 
-		processes = append(processes, process)
+		res := val.(result)
+		if IsProcessGone(res.err) {
+			// Exited since the pids were listed
+			continue
+		}
+		if res.err != nil {
+			partial.add(strconv.Itoa(pid), res.err)
+		}
+		processes = append(processes, res.process)
 	}
 
 	self.List = processes
-	return nil
+	return partial.orNil()
+}
+
+// Gather each composed struct, continuing past errors so that as much as
+// possible is collected. Returns the first error.
+func (self *Process) get(s *LinuxSigar, pid int) error {
+	var firstErr error
+	getters := []func() error{
+		func() error { return self.ProcState.get(s, pid) },
+		func() error { return optionalProcFile(self.ProcIo.get(s, pid)) },
+		func() error { return self.ProcMem.get(s, pid) },
+		func() error { return self.ProcTime.get(s, pid) },
+		func() error { return self.ProcArgs.get(s, pid) },
+		func() error {
+			// Kernel threads have no exe
+			if err := self.ProcExe.get(s, pid); !os.IsNotExist(err) {
+				return optionalProcFile(err)
+			}
+			return nil
+		},
+	}
+	for _, get := range getters {
+		if err := get(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// Drop the errors of process files that only root can read for the processes
+// of other users, so that unprivileged callers get everything else without a
+// failure for every such process. Exits are already caught by ProcState.
+func optionalProcFile(err error) error {
+	if IsPermissionDenied(err) || IsProcessGone(err) {
+		return nil
+	}
+	return err
 }
 
 func (self *ProcList) Get() error {
-	dir, err := os.Open(Procd)
+	return self.get(defaultSigar)
+}
+
+func (self *ProcList) get(s *LinuxSigar) error {
+	dir, err := os.Open(s.procd())
 	if err != nil {
 		return err
 	}
@@ -717,13 +2330,19 @@ func (self *ProcList) Get() error {
 }
 
 func (self *ProcIo) Get(pid int) error {
+	return self.get(defaultSigar, pid)
+}
+
+func (self *ProcIo) get(s *LinuxSigar, pid int) error {
 	assignMap := map[string]*uint64{
 		"syscr:":       &self.ReadOps,
 		"syscw:":       &self.WriteOps,
 		"read_bytes:":  &self.ReadBytes,
 		"write_bytes:": &self.WriteBytes,
 	}
-	err := readFile(fmt.Sprintf("%v/%v/io", Procd, pid), func(line string) bool {
+	path := s.procFileName(pid, "io")
+	var parseErr error
+	err := readFile(path, func(line string) bool {
 		fields := strings.Fields(line)
 		if len(fields) < 2 {
 			return true
@@ -732,46 +2351,184 @@ func (self *ProcIo) Get(pid int) error {
 		if !ok {
 			return true
 		}
-		*val, _ = strtoull(fields[1])
+		var err error
+		*val, err = strtoull(fields[1])
+		if err != nil {
+			parseErr = parseError(path, strings.TrimSuffix(fields[0], ":"), err)
+			return false
+		}
 		return true
 	})
-	return err
+	if err != nil {
+		return readError(path, err, true)
+	}
+	return parseErr
 }
 
 func (self *ProcState) Get(pid int) error {
-	contents, err := readProcFile(pid, "stat")
+	return self.get(defaultSigar, pid)
+}
+
+func (self *ProcState) get(s *LinuxSigar, pid int) error {
+	fields, err := s.readProcStat(pid, 39)
 	if err != nil {
 		return err
 	}
 
-	fields := strings.Fields(string(contents))
-
 	self.Name = fields[1][1 : len(fields[1])-1] // strip ()'s
 
 	self.State = RunState(fields[2][0])
 
 	self.Pid = pid
 
-	self.Ppid, _ = strconv.Atoi(fields[3])
+	ints := []struct {
+		name  string
+		index int
+		val   *int
+	}{
+		{"Ppid", 3, &self.Ppid},
+		{"Tty", 6, &self.Tty},
+		{"Priority", 17, &self.Priority},
+		{"Nice", 18, &self.Nice},
+		{"Processor", 38, &self.Processor},
+	}
+	for _, field := range ints {
+		*field.val, err = strconv.Atoi(fields[field.index])
+		if err != nil {
+			return parseError(s.procFileName(pid, "stat"), field.name, err)
+		}
+	}
 
-	self.Tty, _ = strconv.Atoi(fields[6])
+	return nil
+}
 
-	self.Priority, _ = strconv.Atoi(fields[17])
+func (self *ProcStatus) Get(pid int) error {
+	return self.get(defaultSigar, pid)
+}
 
-	self.Nice, _ = strconv.Atoi(fields[18])
+func (self *ProcStatus) get(s *LinuxSigar, pid int) error {
+	contents, err := s.readProcFile(pid, "status")
+	if err != nil {
+		return err
+	}
+	statusFile := s.procFileName(pid, "status")
+
+	// Kernel threads and older kernels leave out some lines
+	*self = ProcStatus{}
+
+	kbytes := map[string]*uint64{
+		"VmPeak":   &self.VmPeak,
+		"VmHWM":    &self.VmHWM,
+		"VmSwap":   &self.VmSwap,
+		"RssAnon":  &self.RssAnon,
+		"RssFile":  &self.RssFile,
+		"RssShmem": &self.RssShmem,
+	}
+	counts := map[string]*uint64{
+		"Threads":                    &self.Threads,
+		"voluntary_ctxt_switches":    &self.VoluntaryCtxtSwitches,
+		"nonvoluntary_ctxt_switches": &self.NonvoluntaryCtxtSwitches,
+	}
+	masks := map[string]*uint64{
+		"SigPnd": &self.SigPending,
+		"ShdPnd": &self.SigSharedPending,
+		"SigBlk": &self.SigBlocked,
+		"SigIgn": &self.SigIgnored,
+		"SigCgt": &self.SigCaught,
+		"CapInh": &self.CapInheritable,
+		"CapPrm": &self.CapPermitted,
+		"CapEff": &self.CapEffective,
+		"CapBnd": &self.CapBounding,
+		"CapAmb": &self.CapAmbient,
+	}
+	ids := map[string]*ProcIds{
+		"Uid": &self.Uids,
+		"Gid": &self.Gids,
+	}
 
-	self.Processor, _ = strconv.Atoi(fields[38])
+	for _, line := range strings.Split(string(contents), "\n") {
+		sep := strings.IndexByte(line, ':')
+		if sep < 0 {
+			continue
+		}
+		key, value := line[:sep], strings.TrimSpace(line[sep+1:])
+
+		if ptr, ok := kbytes[key]; ok {
+			*ptr, err = strconv.ParseUint(strings.TrimSuffix(value, " kB"), 10, 64)
+			*ptr *= 1024
+		} else if ptr, ok := counts[key]; ok {
+			*ptr, err = strconv.ParseUint(value, 10, 64)
+		} else if ptr, ok := masks[key]; ok {
+			*ptr, err = strconv.ParseUint(value, 16, 64)
+		} else if ptr, ok := ids[key]; ok {
+			*ptr, err = parseProcIds(value)
+		} else {
+			switch key {
+			case "Seccomp":
+				self.Seccomp, err = strconv.Atoi(value)
+			case "NoNewPrivs":
+				self.NoNewPrivs = value == "1"
+			case "Cpus_allowed_list":
+				self.CpusAllowed, err = parseCpuList(value)
+			}
+		}
+		if err != nil {
+			return parseError(statusFile, key, err)
+		}
+	}
 
+	self.User = lookupIdName(s.etcd()+"/passwd", self.Uids.Effective)
+	self.Group = lookupIdName(s.etcd()+"/group", self.Gids.Effective)
 	return nil
 }
 
+// Parse the real, effective, saved and filesystem ids of a Uid or Gid line
+func parseProcIds(value string) (ProcIds, error) {
+	fields := strings.Fields(value)
+	if len(fields) != 4 {
+		return ProcIds{}, fmt.Errorf("Expected 4 ids, got %d", len(fields))
+	}
+	var parsed [4]uint32
+	for i, field := range fields {
+		id, err := strconv.ParseUint(field, 10, 32)
+		if err != nil {
+			return ProcIds{}, err
+		}
+		parsed[i] = uint32(id)
+	}
+	return ProcIds{Real: parsed[0], Effective: parsed[1], Saved: parsed[2], FileSystem: parsed[3]}, nil
+}
+
+// The name of id in a passwd or group file, both of which have the name in
+// the first field and the id in the third. Empty if it isn't found.
+func lookupIdName(file string, id uint32) string {
+	idString := strconv.FormatUint(uint64(id), 10)
+	name := ""
+	readFile(file, func(line string) bool {
+		fields := strings.Split(line, ":")
+		if len(fields) >= 3 && fields[2] == idString && !strings.HasPrefix(fields[0], "#") {
+			name = fields[0]
+			return false
+		}
+		return true
+	})
+	return name
+}
+
 func (self *ProcMem) Get(pid int) error {
-	contents, err := readProcFile(pid, "statm")
+	return self.get(defaultSigar, pid)
+}
+
+func (self *ProcMem) get(s *LinuxSigar, pid int) error {
+	contents, err := s.readProcFile(pid, "statm")
 	if err != nil {
 		return err
 	}
 
 	fields := strings.Fields(string(contents))
+	if len(fields) < 3 {
+		return parseError(s.procFileName(pid, "statm"), "", fmt.Errorf("Expected at least 3 fields, got %d", len(fields)))
+	}
 
 	size, _ := strtoull(fields[0])
 	self.Size = size << 12
@@ -782,13 +2539,11 @@ func (self *ProcMem) Get(pid int) error {
 	share, _ := strtoull(fields[2])
 	self.Share = share << 12
 
-	contents, err = readProcFile(pid, "stat")
+	fields, err = s.readProcStat(pid, 13)
 	if err != nil {
 		return err
 	}
 
-	fields = strings.Fields(string(contents))
-
 	self.MinorFaults, _ = strtoull(fields[10])
 	self.MajorFaults, _ = strtoull(fields[12])
 	self.PageFaults = self.MinorFaults + self.MajorFaults
@@ -797,25 +2552,28 @@ func (self *ProcMem) Get(pid int) error {
 }
 
 func (self *ProcTime) Get(pid int) error {
-	contents, err := readProcFile(pid, "stat")
+	return self.get(defaultSigar, pid)
+}
+
+func (self *ProcTime) get(s *LinuxSigar, pid int) error {
+	fields, err := s.readProcStat(pid, 22)
 	if err != nil {
 		return err
 	}
 
 	self.CollectionTime = time.Now()
-	fields := strings.Fields(string(contents))
 
 	user, _ := strtoull(fields[13])
 	sys, _ := strtoull(fields[14])
 	// convert to millis
-	self.User = user * (1000 / system.ticks)
-	self.Sys = sys * (1000 / system.ticks)
+	self.User = user * (1000 / s.ticks)
+	self.Sys = sys * (1000 / s.ticks)
 	self.Total = self.User + self.Sys
 
 	// convert to millis
 	self.StartTime, _ = strtoull(fields[21])
-	self.StartTime /= system.ticks
-	self.StartTime += system.btime
+	self.StartTime /= s.ticks
+	self.StartTime += s.btime
 	self.StartTime *= 1000
 
 	return err
@@ -851,7 +2609,11 @@ func (self *ProcTime) CalculateCpuPercent(other *ProcTime) error {
 }
 
 func (self *ProcArgs) Get(pid int) error {
-	contents, err := readProcFile(pid, "cmdline")
+	return self.get(defaultSigar, pid)
+}
+
+func (self *ProcArgs) get(s *LinuxSigar, pid int) error {
+	contents, err := s.readProcFile(pid, "cmdline")
 	if err != nil {
 		return err
 	}
@@ -874,6 +2636,10 @@ func (self *ProcArgs) Get(pid int) error {
 }
 
 func (self *ProcExe) Get(pid int) error {
+	return self.get(defaultSigar, pid)
+}
+
+func (self *ProcExe) get(s *LinuxSigar, pid int) error {
 	fields := map[string]*string{
 		"exe":  &self.Name,
 		"cwd":  &self.Cwd,
@@ -881,10 +2647,12 @@ func (self *ProcExe) Get(pid int) error {
 	}
 
 	for name, field := range fields {
-		val, err := os.Readlink(procFileName(pid, name))
+		path := s.procFileName(pid, name)
+		val, err := os.Readlink(path)
 
 		if err != nil {
-			return err
+			// Kernel threads have no exe, so a missing link doesn't mean the process is gone
+			return readError(path, err, false)
 		}
 
 		*field = val
@@ -893,20 +2661,395 @@ func (self *ProcExe) Get(pid int) error {
 	return nil
 }
 
-func parseMeminfo(table map[string]*uint64) error {
-	return readFile(Procd+"/meminfo", func(line string) bool {
-		fields := strings.Split(line, ":")
+func (self *SystemFd) Get() error {
+	return self.get(defaultSigar)
+}
 
-		if ptr := table[fields[0]]; ptr != nil {
-			num := strings.TrimLeft(fields[1], " ")
-			val, err := strtoull(strings.Fields(num)[0])
-			if err == nil {
-				*ptr = val * 1024
+func (self *SystemFd) get(s *LinuxSigar) error {
+	fileNr := s.procd() + "/sys/fs/file-nr"
+	contents, err := ioutil.ReadFile(fileNr)
+	if err != nil {
+		return readError(fileNr, err, false)
+	}
+
+	fields := strings.Fields(string(contents))
+	if len(fields) != 3 {
+		return parseError(fileNr, "", fmt.Errorf("Expected 3 fields, got %d", len(fields)))
+	}
+	values := []*uint64{&self.Allocated, &self.Unused, &self.Max}
+	for i, value := range values {
+		if *value, err = strconv.ParseUint(fields[i], 10, 64); err != nil {
+			return parseError(fileNr, "", err)
+		}
+	}
+
+	// file-nr shows file-max too, but file-max is where it is set
+	fileMax := s.procd() + "/sys/fs/file-max"
+	if line := readFileLine(fileMax); line != "" {
+		if self.Max, err = strconv.ParseUint(line, 10, 64); err != nil {
+			return parseError(fileMax, "", err)
+		}
+	}
+	return nil
+}
+
+func (self *ProcFd) Get(pid int) error {
+	return self.get(defaultSigar, pid)
+}
+
+// The limits are only read once the fds could be counted, so that a zero
+// SoftLimit means nothing was collected, e.g. for processes of other users.
+func (self *ProcFd) get(s *LinuxSigar, pid int) error {
+	fdDir := s.procFileName(pid, "fd")
+	dir, err := os.Open(fdDir)
+	if err != nil {
+		return readError(fdDir, err, true)
+	}
+	names, err := dir.Readdirnames(readAllDirnames)
+	dir.Close()
+	if err != nil {
+		return readError(fdDir, err, true)
+	}
+
+	limits := ProcLimits{}
+	if err := limits.get(s, pid); err != nil {
+		return err
+	}
+
+	self.Open = uint64(len(names))
+	self.SoftLimit = limits.OpenFiles.Soft
+	self.HardLimit = limits.OpenFiles.Hard
+	return nil
+}
+
+func (self *ProcFdUsageList) Get() error {
+	return self.get(defaultSigar)
+}
+
+// Processes that exited, or whose fds can't be read without root, are left out
+func (self *ProcFdUsageList) get(s *LinuxSigar) error {
+	pids := ProcList{}
+	if err := pids.get(s); err != nil {
+		return err
+	}
+
+	list := make([]ProcFdUsage, 0, len(pids.List))
+	partial := &PartialError{}
+	for _, pid := range pids.List {
+		usage := ProcFdUsage{Pid: pid}
+		err := usage.ProcFd.get(s, pid)
+		if IsProcessGone(err) || IsPermissionDenied(err) {
+			continue
+		}
+		if err != nil {
+			partial.add(strconv.Itoa(pid), err)
+			continue
+		}
+		list = append(list, usage)
+	}
+
+	self.List = list
+	return partial.orNil()
+}
+
+func (self *ProcLimits) Get(pid int) error {
+	return self.get(defaultSigar, pid)
+}
+
+func (self *ProcLimits) get(s *LinuxSigar, pid int) error {
+	table := []struct {
+		name  string
+		limit *ProcLimit
+	}{
+		{"Max cpu time", &self.CpuTime},
+		{"Max file size", &self.FileSize},
+		{"Max data size", &self.DataSize},
+		{"Max stack size", &self.StackSize},
+		{"Max core file size", &self.CoreFileSize},
+		{"Max resident set", &self.ResidentSet},
+		{"Max processes", &self.Processes},
+		{"Max open files", &self.OpenFiles},
+		{"Max locked memory", &self.LockedMemory},
+		{"Max address space", &self.AddressSpace},
+		{"Max file locks", &self.FileLocks},
+		{"Max pending signals", &self.PendingSignals},
+		{"Max msgqueue size", &self.MsgqueueSize},
+		{"Max nice priority", &self.NicePriority},
+		{"Max realtime priority", &self.RealtimePriority},
+		{"Max realtime timeout", &self.RealtimeTimeout},
+	}
+
+	limitsFile := s.procFileName(pid, "limits")
+	contents, err := ioutil.ReadFile(limitsFile)
+	if err != nil {
+		return readError(limitsFile, err, true)
+	}
+
+	// Limit names contain spaces, and some limits have no units, so lines are
+	// matched by name rather than split into columns
+	for _, line := range strings.Split(string(contents), "\n") {
+		for _, entry := range table {
+			if !strings.HasPrefix(line, entry.name+" ") {
+				continue
+			}
+			fields := strings.Fields(line[len(entry.name):])
+			if len(fields) < 2 {
+				return parseError(limitsFile, entry.name, errors.New("Missing soft or hard limit"))
+			}
+			if entry.limit.Soft, err = parseLimit(fields[0]); err != nil {
+				return parseError(limitsFile, entry.name, err)
+			}
+			if entry.limit.Hard, err = parseLimit(fields[1]); err != nil {
+				return parseError(limitsFile, entry.name, err)
+			}
+			break
+		}
+	}
+	return nil
+}
+
+func parseLimit(value string) (uint64, error) {
+	if value == "unlimited" {
+		return RlimInfinity, nil
+	}
+	return strconv.ParseUint(value, 10, 64)
+}
+
+func (self *ProcFdList) Get(pid int) error {
+	return self.get(defaultSigar, pid)
+}
+
+func (self *ProcFdList) get(s *LinuxSigar, pid int) error {
+	fdDir := s.procFileName(pid, "fd")
+	dir, err := os.Open(fdDir)
+	if err != nil {
+		return readError(fdDir, err, true)
+	}
+	names, err := dir.Readdirnames(readAllDirnames)
+	dir.Close()
+	if err != nil {
+		return readError(fdDir, err, true)
+	}
+
+	fds := make([]ProcFdEntry, 0, len(names))
+	hasSockets := false
+	for _, name := range names {
+		num, err := strconv.Atoi(name)
+		if err != nil {
+			continue
+		}
+		link, err := os.Readlink(filepath.Join(fdDir, name))
+		if err != nil {
+			// Closed since the directory was listed
+			continue
+		}
+
+		fd := ProcFdEntry{Fd: num, Path: link}
+		fd.Type, fd.Inode = fdType(filepath.Join(fdDir, name), link)
+		if err := fd.readInfo(s.procFileName(pid, "fdinfo/"+name)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		hasSockets = hasSockets || fd.Type == FdSocket
+		fds = append(fds, fd)
+	}
+	sort.Sort(procFdsByFd(fds))
+
+	if hasSockets {
+		conns := s.netConns.get(s, pid)
+		for i := range fds {
+			if conn, ok := conns[fds[i].Inode]; ok && fds[i].Type == FdSocket {
+				conn.Pid = pid
+				fds[i].Socket = &conn
+			}
+		}
+	}
+
+	self.List = fds
+	return nil
+}
+
+type procFdsByFd []ProcFdEntry
+
+func (self procFdsByFd) Len() int           { return len(self) }
+func (self procFdsByFd) Less(i, j int) bool { return self[i].Fd < self[j].Fd }
+func (self procFdsByFd) Swap(i, j int)      { self[i], self[j] = self[j], self[i] }
+
+// The type and inode of an fd, from its link or else a stat through it
+func fdType(path, link string) (ProcFdType, uint64) {
+	switch {
+	case strings.HasPrefix(link, "socket:["):
+		return FdSocket, linkInode(link)
+	case strings.HasPrefix(link, "pipe:["):
+		return FdPipe, linkInode(link)
+	case link == "anon_inode:[eventfd]":
+		return FdEventFd, 0
+	case strings.HasPrefix(link, "anon_inode:"):
+		return FdAnonInode, 0
+	}
+
+	stat := syscall.Stat_t{}
+	if err := syscall.Stat(path, &stat); err != nil {
+		return FdFile, 0
+	}
+	switch stat.Mode & syscall.S_IFMT {
+	case syscall.S_IFDIR:
+		return FdDirectory, uint64(stat.Ino)
+	case syscall.S_IFCHR, syscall.S_IFBLK:
+		return FdDevice, uint64(stat.Ino)
+	case syscall.S_IFIFO:
+		return FdPipe, uint64(stat.Ino)
+	}
+	return FdFile, uint64(stat.Ino)
+}
+
+// The inode in links like "pipe:[12345]"
+func linkInode(link string) uint64 {
+	start := strings.IndexByte(link, '[')
+	if start < 0 || !strings.HasSuffix(link, "]") {
+		return 0
+	}
+	inode, _ := strconv.ParseUint(link[start+1:len(link)-1], 10, 64)
+	return inode
+}
+
+// Read the position, flags and mount of the fd from /proc/<pid>/fdinfo/<fd>
+func (self *ProcFdEntry) readInfo(fdinfoFile string) error {
+	var parseErr error
+	err := readFile(fdinfoFile, func(line string) bool {
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			return true
+		}
+		key := strings.TrimSuffix(fields[0], ":")
+		var err error
+		switch key {
+		case "pos":
+			self.Pos, err = strconv.ParseUint(fields[1], 10, 64)
+		case "flags":
+			self.Flags, err = strconv.ParseUint(fields[1], 8, 64)
+		case "mnt_id":
+			self.MntId, err = strconv.Atoi(fields[1])
+		case "ino":
+			if self.Inode == 0 {
+				self.Inode, err = strconv.ParseUint(fields[1], 10, 64)
+			}
+		}
+		if err != nil {
+			parseErr = parseError(fdinfoFile, key, err)
+			return false
+		}
+		return true
+	})
+	if err != nil {
+		return err
+	}
+	return parseErr
+}
+
+// How long the sockets of a network namespace are reused, long enough to list
+// the fds of every process without re-reading the same tables for each one
+const netConnCacheAge = time.Second
+
+// The sockets of each network namespace, by the inode of /proc/<pid>/ns/net
+type netConnCache struct {
+	sync.Mutex
+	byNs map[uint64]netConnTables
+}
+
+type netConnTables struct {
+	read  time.Time
+	conns map[uint64]NetConn
+}
+
+// The sockets of the network namespace of pid, by inode. The map is shared
+// with other callers and must not be modified.
+func (self *netConnCache) get(s *LinuxSigar, pid int) map[uint64]NetConn {
+	stat := syscall.Stat_t{}
+	if err := syscall.Stat(s.procFileName(pid, "ns/net"), &stat); err != nil {
+		// Kernels before 3.0 have no ns/net
+		return readPidConns(s, pid)
+	}
+	ns := uint64(stat.Ino)
+
+	self.Lock()
+	defer self.Unlock()
+	now := time.Now()
+	if tables, ok := self.byNs[ns]; ok && now.Sub(tables.read) < netConnCacheAge {
+		return tables.conns
+	}
+	if self.byNs == nil {
+		self.byNs = make(map[uint64]netConnTables)
+	}
+	for inode, tables := range self.byNs {
+		if now.Sub(tables.read) >= netConnCacheAge {
+			delete(self.byNs, inode)
+		}
+	}
+	conns := readPidConns(s, pid)
+	self.byNs[ns] = netConnTables{read: now, conns: conns}
+	return conns
+}
+
+// The TCP, UDP and raw sockets of the network namespace of pid, by inode
+func readPidConns(s *LinuxSigar, pid int) map[uint64]NetConn {
+	conns := make(map[uint64]NetConn)
+	for _, table := range procNetConnFiles {
+		list, err := readConnList(s.procFileName(pid, table.name), table.proto, table.ipSizeBytes, table.numFields)
+		if err != nil {
+			// e.g. no IPv6
+			continue
+		}
+		for _, conn := range list {
+			if conn.Inode != 0 {
+				conns[conn.Inode] = conn
 			}
 		}
+	}
+	return conns
+}
+
+// Parse /proc/meminfo into table, converting values in kB to bytes. Every
+// key, including those in table, is also passed to each if it isn't nil.
+func (s *LinuxSigar) parseMeminfo(table map[string]*uint64, each func(key string, value uint64)) error {
+	meminfoFile := s.procd() + "/meminfo"
+
+	var parseErr error
+	err := readFile(meminfoFile, func(line string) bool {
+		fields := strings.Split(line, ":")
+		if len(fields) != 2 {
+			return true
+		}
+
+		ptr := table[fields[0]]
+		if ptr == nil && each == nil {
+			return true
+		}
+
+		valueFields := strings.Fields(fields[1])
+		if len(valueFields) == 0 {
+			return true
+		}
+		val, err := strtoull(valueFields[0])
+		if err != nil {
+			parseErr = parseError(meminfoFile, fields[0], err)
+			return false
+		}
+		if len(valueFields) > 1 && valueFields[1] == "kB" {
+			val *= 1024
+		}
 
+		if ptr != nil {
+			*ptr = val
+		}
+		if each != nil {
+			each(fields[0], val)
+		}
 		return true
 	})
+	if err != nil {
+		return err
+	}
+	return parseErr
 }
 
 func parseCpuStat(self *Cpu, line string) error {
@@ -924,14 +3067,39 @@ func parseCpuStat(self *Cpu, line string) error {
 	if len(fields) > 9 {
 		self.Guest, _ = strtoull(fields[9])
 	}
+	/* Guest nice was added in 2.6.33 */
+	if len(fields) > 10 {
+		self.GuestNice, _ = strtoull(fields[10])
+	}
 
 	return nil
 }
 
+// Undo the octal escaping of spaces, tabs, newlines and backslashes in paths
+// listed by /proc/swaps and /proc/self/mountinfo, e.g. \040 for a space.
+func unescapeOctal(str string) string {
+	if !strings.Contains(str, "\\") {
+		return str
+	}
+
+	var buf bytes.Buffer
+	for i := 0; i < len(str); i++ {
+		if str[i] == '\\' && i+3 < len(str) {
+			if c, err := strconv.ParseUint(str[i+1:i+4], 8, 8); err == nil {
+				buf.WriteByte(byte(c))
+				i += 3
+				continue
+			}
+		}
+		buf.WriteByte(str[i])
+	}
+	return buf.String()
+}
+
 func readFile(file string, handler func(string) bool) error {
 	contents, err := ioutil.ReadFile(file)
 	if err != nil {
-		return err
+		return readError(file, err, false)
 	}
 
 	reader := bufio.NewReader(bytes.NewBuffer(contents))
@@ -975,22 +3143,64 @@ func strtoull(val string) (uint64, error) {
 	return strconv.ParseUint(val, 10, 64)
 }
 
-func procFileName(pid int, name string) string {
-	return Procd + "/" + strconv.Itoa(pid) + "/" + name
+func (s *LinuxSigar) procFileName(pid int, name string) string {
+	return s.procd() + "/" + strconv.Itoa(pid) + "/" + name
+}
+
+// Read /proc/<pid>/stat, splitting it into at least minFields fields. The
+// command name is kept as a single field, as it may contain spaces.
+func (s *LinuxSigar) readProcStat(pid int, minFields int) ([]string, error) {
+	contents, err := s.readProcFile(pid, "stat")
+	if err != nil {
+		return nil, err
+	}
+
+	stat := string(contents)
+	fields := strings.Fields(stat)
+	start := strings.IndexByte(stat, '(')
+	end := strings.LastIndexByte(stat, ')')
+	if start >= 0 && end > start {
+		fields = append(strings.Fields(stat[:start]), stat[start:end+1])
+		fields = append(fields, strings.Fields(stat[end+1:])...)
+	}
+
+	if len(fields) < minFields {
+		return nil, parseError(s.procFileName(pid, "stat"), "", fmt.Errorf("Expected at least %d fields, got %d", minFields, len(fields)))
+	}
+	return fields, nil
 }
 
-func readProcFile(pid int, name string) ([]byte, error) {
-	path := procFileName(pid, name)
+func (s *LinuxSigar) readProcFile(pid int, name string) ([]byte, error) {
+	path := s.procFileName(pid, name)
 	contents, err := ioutil.ReadFile(path)
 	if err != nil {
-		if perr, ok := err.(*os.PathError); ok {
-			if perr.Err == syscall.ENOENT {
-				return nil, syscall.ESRCH
-			}
-		}
+		return nil, readError(path, err, true)
 	}
 
-	return contents, err
+	return contents, nil
+}
+
+// Fallback for kernels without sysfs: list all the partitions, and check the
+// major/minor device ID to find which are devices vs. partitions (ex. sda v. sda1)
+func readPartitions(s *LinuxSigar, devices map[string]bool) error {
+	return readFile(s.procd()+"/partitions", func(line string) bool {
+		fields := strings.Fields(line)
+		if len(fields) < 4 {
+			return true
+		}
+		majorDevId, err := strtoull(fields[0])
+		if err != nil {
+			return true
+		}
+		minorDevId, err := strtoull(fields[1])
+		if err != nil {
+			return true
+		}
+		if isNotPartition(majorDevId, minorDevId) {
+			devices[fields[3]] = true
+		}
+		return true
+	})
 }
 
 /* For SCSI and IDE devices, only display devices and not individual partitions.
@@ -1030,17 +3240,24 @@ func (self *SystemInfo) Get() error {
 var distributionDesc string = "DISTRIB_DESCRIPTION="
 
 func (self *SystemDistribution) Get() error {
-	// Special case for redhat/centos, ignoring any error
-	_ = readFile(Etcd+"/redhat-release", func(line string) bool {
+	return self.get(defaultSigar)
+}
+
+func (self *SystemDistribution) get(s *LinuxSigar) error {
+	// Special case for redhat/centos, falling back to lsb-release if it doesn't exist
+	err := readFile(s.etcd()+"/redhat-release", func(line string) bool {
 		self.Description = line
 		return false
 	})
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
 	if self.Description != "" {
 		return nil
 	}
 
 	// Read /etc/lsb-release
-	return readFile(Etcd+"/lsb-release", func(line string) bool {
+	return readFile(s.etcd()+"/lsb-release", func(line string) bool {
 		if strings.HasPrefix(line, distributionDesc) {
 			self.Description = strings.Trim(line[len(distributionDesc):], `"`)
 			return false
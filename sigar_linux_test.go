@@ -1,10 +1,14 @@
 package sigar_test
 
 import (
+	"context"
+	"fmt"
 	"io/ioutil"
 	"net"
 	"os"
+	"path/filepath"
 	"strings"
+	"syscall"
 	"time"
 
 	. "github.com/scalingdata/ginkgo"
@@ -45,6 +49,66 @@ var _ = Describe("sigarLinux", func() {
 		Expect(sigar.ReadUint("abc")).To(Equal(uint64(0)))
 	})
 
+	Describe("New", func() {
+		var otherProcd string
+
+		BeforeEach(func() {
+			var err error
+			otherProcd, err = ioutil.TempDir("", "sigarTests")
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			os.RemoveAll(otherProcd)
+		})
+
+		It("reads from its own roots independently of other instances", func() {
+			err := ioutil.WriteFile(procd+"/loadavg", []byte("1.00 2.00 3.00 1/100 1000"), 0644)
+			Expect(err).ToNot(HaveOccurred())
+			err = ioutil.WriteFile(otherProcd+"/loadavg", []byte("4.00 5.00 6.00 1/100 1000"), 0644)
+			Expect(err).ToNot(HaveOccurred())
+
+			host := sigar.New(sigar.Options{ProcRoot: otherProcd})
+			container := sigar.New(sigar.Options{ProcRoot: procd})
+
+			hostAvg, err := host.GetLoadAverage()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(hostAvg.One).To(Equal(4.0))
+
+			containerAvg, err := container.GetLoadAverage()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(containerAvg.One).To(Equal(1.0))
+		})
+
+		It("falls back to the package roots when none are given", func() {
+			err := ioutil.WriteFile(procd+"/loadavg", []byte("1.00 2.00 3.00 1/100 1000"), 0644)
+			Expect(err).ToNot(HaveOccurred())
+
+			avg, err := sigar.New(sigar.Options{}).GetLoadAverage()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(avg.Fifteen).To(Equal(3.0))
+		})
+
+		It("loads boot time from its own proc root", func() {
+			err := ioutil.WriteFile(otherProcd+"/stat", []byte("cpu 25 1 2 3 4 5 6 7\nbtime 1494680071"), 0644)
+			Expect(err).ToNot(HaveOccurred())
+			statLine := "10 (stress) R 25372 25372 10153 34819 25372 4202560 34 0 0 0 7238 16 0 0 20 0 1 0 29081667 6676480 50 18446744073709551615 4194304 4213484 140721323475968 140721323475512 140017284985275 0 0 0 0 0 0 0 17 0 0 0 0 0 0"
+			err = os.MkdirAll(otherProcd+"/10/", 0777)
+			Expect(err).ToNot(HaveOccurred())
+			err = ioutil.WriteFile(otherProcd+"/10/stat", []byte(statLine), 0644)
+			Expect(err).ToNot(HaveOccurred())
+
+			procTime, err := sigar.New(sigar.Options{ProcRoot: otherProcd}).GetProcTime(10)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(procTime.StartTime).To(Equal(uint64(1494970887000)))
+		})
+
+		It("implements the Sigar interface", func() {
+			var s sigar.Sigar = sigar.New(sigar.Options{})
+			Expect(s).ToNot(BeNil())
+		})
+	})
+
 	Describe("CPU", func() {
 		var (
 			statFile string
@@ -174,14 +238,283 @@ var _ = Describe("sigarLinux", func() {
 				stop <- struct{}{}
 			})
 		})
+
+		Describe("KernelStat", func() {
+			It("gets kernel activity counters", func() {
+				statContents := []byte("cpu 25 1 2 3 4 5 6 7 8 9\n" +
+					"intr 1000 10 20 0 0\n" +
+					"ctxt 5000\n" +
+					"btime 1494970887\n" +
+					"processes 300\n" +
+					"procs_running 3\n" +
+					"procs_blocked 1\n" +
+					"softirq 700 1 2 3 4 5 6 7 8 9 10\n")
+				err := ioutil.WriteFile(statFile, statContents, 0644)
+				Expect(err).ToNot(HaveOccurred())
+
+				kernelStat := sigar.KernelStat{}
+				err = kernelStat.Get()
+				Expect(err).ToNot(HaveOccurred())
+				Expect(kernelStat).To(Equal(sigar.KernelStat{
+					ContextSwitches: 5000,
+					Interrupts:      1000,
+					SoftIrqs:        700,
+					Forks:           300,
+					ProcsRunning:    3,
+					ProcsBlocked:    1,
+					BootTime:        1494970887,
+				}))
+
+				err = cpu.Get()
+				Expect(err).ToNot(HaveOccurred())
+				Expect(cpu.GuestNice).To(Equal(uint64(9)))
+			})
+
+			It("returns a parse error for malformed counters", func() {
+				err := ioutil.WriteFile(statFile, []byte("ctxt lots\n"), 0644)
+				Expect(err).ToNot(HaveOccurred())
+
+				kernelStat := sigar.KernelStat{}
+				err = kernelStat.Get()
+				Expect(sigar.IsParse(err)).To(BeTrue())
+				Expect(err.(*sigar.Error).Field).To(Equal("ctxt"))
+			})
+
+			It("converts deltas to rates", func() {
+				previous := sigar.KernelStat{ContextSwitches: 1000, Forks: 10, ProcsRunning: 7}
+				current := sigar.KernelStat{ContextSwitches: 3000, Forks: 30, ProcsRunning: 2}
+
+				delta := current.Delta(previous)
+				Expect(delta.ProcsRunning).To(Equal(uint64(2)))
+
+				rate := delta.PerSecond(2 * time.Second)
+				Expect(rate.ContextSwitches).To(Equal(1000.0))
+				Expect(rate.Forks).To(Equal(10.0))
+			})
+		})
+
+		Describe("CpuList", func() {
+			It("indexes CPUs by id, leaving offline CPUs empty", func() {
+				statContents := []byte("cpu 30 0 0 30 0 0 0 0\ncpu0 10 0 0 10 0 0 0 0\ncpu2 20 0 0 20 0 0 0 0\n")
+				err := ioutil.WriteFile(statFile, statContents, 0644)
+				Expect(err).ToNot(HaveOccurred())
+
+				cpuList := sigar.CpuList{}
+				err = cpuList.Get()
+				Expect(err).ToNot(HaveOccurred())
+				Expect(cpuList.List).To(Equal([]sigar.Cpu{{User: 10, Idle: 10}, {}, {User: 20, Idle: 20}}))
+			})
+		})
+
+		Describe("CpuInfo", func() {
+			var cpuDir string
+
+			writeSys := func(file, contents string) {
+				err := os.MkdirAll(filepath.Dir(cpuDir+"/"+file), 0755)
+				Expect(err).ToNot(HaveOccurred())
+				err = ioutil.WriteFile(cpuDir+"/"+file, []byte(contents+"\n"), 0444)
+				Expect(err).ToNot(HaveOccurred())
+			}
+
+			BeforeEach(func() {
+				cpuinfoContents := "processor\t: 0\n" +
+					"vendor_id\t: GenuineIntel\n" +
+					"model name\t: Intel(R) Xeon(R) CPU\n" +
+					"cpu MHz\t\t: 2100.000\n" +
+					"physical id\t: 0\n" +
+					"core id\t\t: 0\n" +
+					"flags\t\t: fpu vme sse2\n" +
+					"\n" +
+					"processor\t: 1\n" +
+					"vendor_id\t: GenuineIntel\n" +
+					"model name\t: Intel(R) Xeon(R) CPU\n" +
+					"cpu MHz\t\t: 2100.000\n" +
+					"flags\t\t: fpu vme sse2\n"
+				err := ioutil.WriteFile(procd+"/cpuinfo", []byte(cpuinfoContents), 0444)
+				Expect(err).ToNot(HaveOccurred())
+
+				cpuDir = sysd + "/devices/system/cpu"
+				writeSys("online", "0-1")
+				writeSys("cpu0/topology/physical_package_id", "0")
+				writeSys("cpu0/topology/core_id", "0")
+				writeSys("cpu0/topology/thread_siblings_list", "0-1")
+				writeSys("cpu0/cpufreq/scaling_cur_freq", "1200000")
+				writeSys("cpu0/cpufreq/cpuinfo_min_freq", "800000")
+				writeSys("cpu0/cpufreq/cpuinfo_max_freq", "3500000")
+				writeSys("cpu0/cpufreq/scaling_governor", "powersave")
+				writeSys("cpu1/topology/physical_package_id", "0")
+				writeSys("cpu1/topology/core_id", "0")
+				writeSys("cpu1/topology/thread_siblings_list", "0-1")
+				writeSys("cpu2/online", "0")
+			})
+
+			It("combines cpuinfo with the sysfs topology and frequencies", func() {
+				cpuInfo := sigar.CpuInfo{}
+				err := cpuInfo.Get()
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(cpuInfo.List).To(HaveLen(3))
+				Expect(cpuInfo.List[0]).To(Equal(sigar.LogicalCpu{
+					Id:        0,
+					Online:    true,
+					Vendor:    "GenuineIntel",
+					ModelName: "Intel(R) Xeon(R) CPU",
+					Flags:     []string{"fpu", "vme", "sse2"},
+					SocketId:  0,
+					CoreId:    0,
+					ThreadId:  0,
+					CurFreq:   1200000000,
+					MinFreq:   800000000,
+					MaxFreq:   3500000000,
+					Governor:  "powersave",
+				}))
+
+				// No cpufreq, so the current frequency comes from cpuinfo
+				Expect(cpuInfo.List[1].ThreadId).To(Equal(1))
+				Expect(cpuInfo.List[1].CurFreq).To(Equal(uint64(2100000000)))
+				Expect(cpuInfo.List[1].MaxFreq).To(BeZero())
+				Expect(cpuInfo.Online()).To(Equal(2))
+			})
+
+			It("lists offline CPUs with an unknown topology", func() {
+				cpuInfo := sigar.CpuInfo{}
+				err := cpuInfo.Get()
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(cpuInfo.List[2]).To(Equal(sigar.LogicalCpu{Id: 2, SocketId: -1, CoreId: -1, ThreadId: -1}))
+			})
+
+			It("falls back to the per-CPU online files", func() {
+				err := os.Remove(cpuDir + "/online")
+				Expect(err).ToNot(HaveOccurred())
+
+				cpuInfo := sigar.CpuInfo{}
+				err = cpuInfo.Get()
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(cpuInfo.List[0].Online).To(BeTrue())
+				Expect(cpuInfo.List[2].Online).To(BeFalse())
+			})
+		})
+
+		Describe("CollectCpuListPercent", func() {
+			It("collects per-core utilization, ignoring hot-plugged cores", func() {
+				statContents := []byte("cpu 10 0 0 30 0 0 0 0\ncpu0 10 0 0 30 0 0 0 0\n")
+				err := ioutil.WriteFile(statFile, statContents, 0644)
+				Expect(err).ToNot(HaveOccurred())
+
+				concreteSigar := &sigar.ConcreteSigar{}
+				percents, stop := concreteSigar.CollectCpuListPercent(500 * time.Millisecond)
+
+				first := <-percents
+				Expect(first.List).To(Equal([]sigar.CpuPercent{{User: 0.25, Idle: 0.75}}))
+
+				statContents = []byte("cpu 110 0 0 80 0 0 0 0\ncpu0 110 0 0 30 0 0 0 0\ncpu1 400 0 0 600 0 0 0 0\n")
+				err = ioutil.WriteFile(statFile, statContents, 0644)
+				Expect(err).ToNot(HaveOccurred())
+
+				second := <-percents
+				Expect(second.List).To(Equal([]sigar.CpuPercent{{User: 1}, {}}))
+				Expect(second.Total.Busy()).To(Equal(1.0))
+
+				stop <- struct{}{}
+			})
+		})
+	})
+
+	Describe("Pressure", func() {
+		var pressureDir string
+
+		BeforeEach(func() {
+			pressureDir = procd + "/pressure"
+			err := os.MkdirAll(pressureDir, 0755)
+			Expect(err).ToNot(HaveOccurred())
+
+			for _, resource := range []string{"cpu", "memory", "io"} {
+				contents := []byte("some avg10=1.50 avg60=0.75 avg300=0.25 total=123456\n" +
+					"full avg10=0.00 avg60=0.00 avg300=0.00 total=42\n")
+				err = ioutil.WriteFile(pressureDir+"/"+resource, contents, 0644)
+				Expect(err).ToNot(HaveOccurred())
+			}
+		})
+
+		It("gets pressure stall information", func() {
+			pressure := sigar.Pressure{}
+			err := pressure.Get()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(pressure.Memory.Some).To(Equal(sigar.PressureStat{Avg10: 1.5, Avg60: 0.75, Avg300: 0.25, Total: 123456}))
+			Expect(pressure.Io.Full.Total).To(Equal(uint64(42)))
+		})
+
+		It("gets the pressure of a cgroup", func() {
+			cgroupDir := sysd + "/fs/cgroup/system.slice"
+			err := os.MkdirAll(cgroupDir, 0755)
+			Expect(err).ToNot(HaveOccurred())
+			for _, resource := range []string{"cpu", "memory", "io"} {
+				err = ioutil.WriteFile(cgroupDir+"/"+resource+".pressure", []byte("some avg10=0.00 avg60=0.00 avg300=0.00 total=7\n"), 0644)
+				Expect(err).ToNot(HaveOccurred())
+			}
+
+			pressure := sigar.Pressure{}
+			err = pressure.GetCgroup("system.slice")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(pressure.Cpu.Some.Total).To(Equal(uint64(7)))
+		})
+
+		It("gets the pressure of a cgroup on a hybrid hierarchy", func() {
+			cgroupDir := sysd + "/fs/cgroup/unified/system.slice"
+			err := os.MkdirAll(cgroupDir, 0755)
+			Expect(err).ToNot(HaveOccurred())
+			for _, resource := range []string{"cpu", "memory", "io"} {
+				err = ioutil.WriteFile(cgroupDir+"/"+resource+".pressure", []byte("some avg10=0.00 avg60=0.00 avg300=0.00 total=9\n"), 0644)
+				Expect(err).ToNot(HaveOccurred())
+			}
+
+			pressure := sigar.Pressure{}
+			err = pressure.GetCgroup("system.slice")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(pressure.Io.Some.Total).To(Equal(uint64(9)))
+
+			err = pressure.GetCgroup("user.slice")
+			Expect(sigar.IsNotSupported(err)).To(BeTrue())
+		})
+
+		It("reports kernels without PSI as not supported", func() {
+			err := os.RemoveAll(pressureDir)
+			Expect(err).ToNot(HaveOccurred())
+
+			pressure := sigar.Pressure{}
+			err = pressure.Get()
+			Expect(sigar.IsNotSupported(err)).To(BeTrue())
+			Expect(err.(*sigar.Error).Path).To(Equal(pressureDir + "/cpu"))
+		})
+
+		It("returns a parse error for malformed values", func() {
+			err := ioutil.WriteFile(pressureDir+"/io", []byte("some avg10=high total=1\n"), 0644)
+			Expect(err).ToNot(HaveOccurred())
+
+			pressure := sigar.Pressure{}
+			err = pressure.Get()
+			Expect(sigar.IsParse(err)).To(BeTrue())
+			Expect(err.(*sigar.Error).Field).To(Equal("some avg10"))
+		})
+
+		It("converts total deltas to stalled fractions", func() {
+			previous := sigar.PressureStat{Total: 1000000}
+			current := sigar.PressureStat{Avg10: 20, Total: 1500000}
+
+			delta := current.Delta(previous)
+			Expect(delta.Avg10).To(Equal(20.0))
+			Expect(delta.Stalled(2 * time.Second)).To(Equal(0.25))
+		})
 	})
 
 	Describe("Mem", func() {
-		var meminfoFile string
+		var meminfoFile, meminfoContents string
 		BeforeEach(func() {
 			meminfoFile = procd + "/meminfo"
 
-			meminfoContents := `
+			meminfoContents = `
 MemTotal:         374256 kB
 MemFree:          274460 kB
 Buffers:            9764 kB
@@ -237,6 +570,316 @@ DirectMap2M:      333824 kB
 			Expect(mem.Total).To(BeNumerically("==", 374256*1024))
 			Expect(mem.Free).To(BeNumerically("==", 274460*1024))
 		})
+
+		It("estimates free memory without MemAvailable, excluding shmem and including reclaimable slab", func() {
+			mem := sigar.Mem{}
+			err := mem.Get()
+			Expect(err).ToNot(HaveOccurred())
+
+			actualFree := (274460 + 9764 + 38648 + 9128 - 584) * 1024
+			Expect(mem.ActualFree).To(BeNumerically("==", actualFree))
+			Expect(mem.ActualUsed).To(BeNumerically("==", 374256*1024-actualFree))
+		})
+
+		It("uses MemAvailable when the kernel provides it", func() {
+			meminfoContents := "MemTotal: 374256 kB\nMemFree: 274460 kB\nMemAvailable: 300000 kB\nCached: 38648 kB\n"
+			err := ioutil.WriteFile(meminfoFile, []byte(meminfoContents), 0444)
+			Expect(err).ToNot(HaveOccurred())
+
+			mem := sigar.Mem{}
+			err = mem.Get()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(mem.ActualFree).To(BeNumerically("==", 300000*1024))
+			Expect(mem.ActualUsed).To(BeNumerically("==", (374256-300000)*1024))
+		})
+
+		It("uses MemAvailable when the kernel reports none available", func() {
+			meminfoContents := "MemTotal: 374256 kB\nMemFree: 274460 kB\nMemAvailable: 0 kB\nCached: 38648 kB\n"
+			err := ioutil.WriteFile(meminfoFile, []byte(meminfoContents), 0444)
+			Expect(err).ToNot(HaveOccurred())
+
+			mem := sigar.Mem{}
+			err = mem.Get()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(mem.ActualFree).To(BeNumerically("==", 0))
+			Expect(mem.ActualUsed).To(BeNumerically("==", 374256*1024))
+		})
+
+		It("returns every meminfo field", func() {
+			err := ioutil.WriteFile(meminfoFile, []byte(meminfoContents+"Zswap:   12 kB\nFutureCount:   3\n"), 0444)
+			Expect(err).ToNot(HaveOccurred())
+
+			info := sigar.MemInfo{}
+			err = info.Get()
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(info.SReclaimable).To(BeNumerically("==", 9128*1024))
+			Expect(info.ActiveAnon).To(BeNumerically("==", 16572*1024))
+			Expect(info.CommittedAS).To(BeNumerically("==", 55880*1024))
+			Expect(info.NFSUnstable).To(BeNumerically("==", 0))
+			Expect(info.HugePagesTotal).To(BeNumerically("==", 0))
+			Expect(info.Hugepagesize).To(BeNumerically("==", 2048*1024))
+			Expect(info.DirectMap2M).To(BeNumerically("==", 333824*1024))
+			Expect(info.Other).To(Equal(map[string]uint64{"Zswap": 12 * 1024, "FutureCount": 3}))
+			Expect(info.HasMemAvailable).To(BeFalse())
+		})
+
+		It("tells a zero MemAvailable from a missing one", func() {
+			err := ioutil.WriteFile(meminfoFile, []byte("MemTotal: 374256 kB\nMemAvailable: 0 kB\n"), 0444)
+			Expect(err).ToNot(HaveOccurred())
+
+			info := sigar.MemInfo{}
+			err = info.Get()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(info.MemAvailable).To(BeNumerically("==", 0))
+			Expect(info.HasMemAvailable).To(BeTrue())
+			Expect(info.Other).To(BeEmpty())
+		})
+
+		It("returns a parse error for malformed values", func() {
+			err := ioutil.WriteFile(meminfoFile, []byte("MemTotal: lots kB\n"), 0444)
+			Expect(err).ToNot(HaveOccurred())
+
+			info := sigar.MemInfo{}
+			err = info.Get()
+			Expect(sigar.IsParse(err)).To(BeTrue())
+			Expect(err.(*sigar.Error).Field).To(Equal("MemTotal"))
+		})
+	})
+
+	Describe("VmStat", func() {
+		var vmstatFile string
+
+		BeforeEach(func() {
+			vmstatFile = procd + "/vmstat"
+			vmstatContents := `nr_free_pages 123456
+nr_dirty 42
+pgpgin 1000
+pgpgout 2000
+pswpin 30
+pswpout 40
+pgfault 50000
+pgmajfault 60
+pgscan_kswapd 700
+pgscan_direct 80
+pgsteal_kswapd 650
+pgsteal_direct 75
+oom_kill 2
+thp_fault_alloc 11
+thp_split_page 3
+workingset_refault_file 900
+`
+			err := ioutil.WriteFile(vmstatFile, []byte(vmstatContents), 0444)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("gets virtual memory counters", func() {
+			vmstat := sigar.VmStat{}
+			err := vmstat.Get()
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(vmstat.PgpgIn).To(Equal(uint64(1000)))
+			Expect(vmstat.PswpOut).To(Equal(uint64(40)))
+			Expect(vmstat.PgMajFault).To(Equal(uint64(60)))
+			Expect(vmstat.PgScanDirect).To(Equal(uint64(80)))
+			Expect(vmstat.PgStealKswapd).To(Equal(uint64(650)))
+			Expect(vmstat.OomKill).To(Equal(uint64(2)))
+			Expect(vmstat.ThpFaultAlloc).To(Equal(uint64(11)))
+			Expect(vmstat.Other).To(Equal(map[string]uint64{
+				"nr_free_pages":           123456,
+				"nr_dirty":                42,
+				"workingset_refault_file": 900,
+			}))
+		})
+
+		It("returns a parse error for malformed values", func() {
+			err := ioutil.WriteFile(vmstatFile, []byte("pswpin many\n"), 0444)
+			Expect(err).ToNot(HaveOccurred())
+
+			vmstat := sigar.VmStat{}
+			err = vmstat.Get()
+			Expect(sigar.IsParse(err)).To(BeTrue())
+			Expect(err.(*sigar.Error).Field).To(Equal("pswpin"))
+		})
+
+		It("converts deltas to rates, keeping gauges as they are", func() {
+			previous := sigar.VmStat{PswpIn: 100, OomKill: 1, Other: map[string]uint64{"nr_dirty": 10, "pgrefill": 50}}
+			current := sigar.VmStat{PswpIn: 300, OomKill: 2, Other: map[string]uint64{"nr_dirty": 4, "pgrefill": 90}}
+
+			delta := current.Delta(previous)
+			Expect(delta.PswpIn).To(Equal(uint64(200)))
+			Expect(delta.OomKill).To(Equal(uint64(1)))
+			Expect(delta.Other).To(Equal(map[string]uint64{"nr_dirty": 4, "pgrefill": 40}))
+
+			rate := delta.PerSecond(2 * time.Second)
+			Expect(rate.PswpIn).To(Equal(100.0))
+			Expect(rate.Other).To(Equal(map[string]float64{"pgrefill": 20}))
+		})
+	})
+
+	Describe("SwapList", func() {
+		var swapsFile string
+
+		BeforeEach(func() {
+			swapsFile = procd + "/swaps"
+			swapsContents := "Filename\t\t\t\tType\t\tSize\t\tUsed\t\tPriority\n" +
+				"/dev/zram0                              partition\t4000000\t\t1000\t\t100\n" +
+				"/var/swap\\040file                        file\t\t2097148\t\t0\t\t-2\n"
+			err := ioutil.WriteFile(swapsFile, []byte(swapsContents), 0444)
+			Expect(err).ToNot(HaveOccurred())
+
+			err = os.MkdirAll(sysd+"/block/zram0", 0755)
+			Expect(err).ToNot(HaveOccurred())
+			err = ioutil.WriteFile(sysd+"/block/zram0/mm_stat", []byte("  4096000   1024000   1200000        0  1300000      10       2       1\n"), 0444)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("lists swap devices, with zram compression stats", func() {
+			swapList := sigar.SwapList{}
+			err := swapList.Get()
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(swapList.List).To(Equal([]sigar.SwapDevice{
+				{
+					Name:     "/dev/zram0",
+					Type:     "partition",
+					Size:     4000000 * 1024,
+					Used:     1000 * 1024,
+					Priority: 100,
+					IsZram:   true,
+					Zram: sigar.ZramStat{
+						OrigDataSize:   4096000,
+						ComprDataSize:  1024000,
+						MemUsedTotal:   1200000,
+						MemUsedMax:     1300000,
+						SamePages:      10,
+						PagesCompacted: 2,
+						HugePages:      1,
+					},
+				},
+				{
+					Name:     "/var/swap file",
+					Type:     "file",
+					Size:     2097148 * 1024,
+					Priority: -2,
+				},
+			}))
+			Expect(swapList.List[0].Zram.CompressionRatio()).To(BeNumerically("~", 3.41, 0.01))
+		})
+
+		It("leaves zram stats empty on kernels without mm_stat", func() {
+			err := os.Remove(sysd + "/block/zram0/mm_stat")
+			Expect(err).ToNot(HaveOccurred())
+
+			swapList := sigar.SwapList{}
+			err = swapList.Get()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(swapList.List[0].IsZram).To(BeTrue())
+			Expect(swapList.List[0].Zram).To(Equal(sigar.ZramStat{}))
+		})
+
+		It("returns a parse error for malformed sizes", func() {
+			err := ioutil.WriteFile(swapsFile, []byte("/swapfile file big 0 -2\n"), 0444)
+			Expect(err).ToNot(HaveOccurred())
+
+			swapList := sigar.SwapList{}
+			err = swapList.Get()
+			Expect(sigar.IsParse(err)).To(BeTrue())
+			Expect(err.(*sigar.Error).Field).To(Equal("Size"))
+		})
+	})
+
+	Describe("NumaNodeList", func() {
+		var nodeDir string
+
+		writeNode := func(id, cpulist, distance, memTotal, memFree, numaHit string) {
+			dir := nodeDir + "/node" + id
+			err := os.MkdirAll(dir, 0755)
+			Expect(err).ToNot(HaveOccurred())
+
+			meminfo := "Node " + id + " MemTotal:       " + memTotal + " kB\n" +
+				"Node " + id + " MemFree:        " + memFree + " kB\n" +
+				"Node " + id + " MemUsed:        1000 kB\n" +
+				"Node " + id + " Active:         500 kB\n"
+			numastat := "numa_hit " + numaHit + "\nnuma_miss 2\nnuma_foreign 3\n" +
+				"interleave_hit 4\nlocal_node 5\nother_node 6\n"
+			files := map[string]string{
+				"cpulist":  cpulist + "\n",
+				"distance": distance + "\n",
+				"meminfo":  meminfo,
+				"numastat": numastat,
+			}
+			for name, contents := range files {
+				err = ioutil.WriteFile(dir+"/"+name, []byte(contents), 0444)
+				Expect(err).ToNot(HaveOccurred())
+			}
+		}
+
+		BeforeEach(func() {
+			nodeDir = sysd + "/devices/system/node"
+			writeNode("1", "4-5,7", "20 10", "2000", "1000", "100")
+			writeNode("0", "0-3,6", "10 20", "3000", "2000", "200")
+
+			err := ioutil.WriteFile(nodeDir+"/possible", []byte("0-1\n"), 0444)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("lists nodes in id order, with memory, counters, CPUs and distances", func() {
+			nodeList := sigar.NumaNodeList{}
+			err := nodeList.Get()
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(nodeList.List).To(HaveLen(2))
+			Expect(nodeList.List[0]).To(Equal(sigar.NumaNode{
+				Id:        0,
+				Cpus:      []int{0, 1, 2, 3, 6},
+				Distances: []int{10, 20},
+				MemTotal:  3000 * 1024,
+				MemFree:   2000 * 1024,
+				MemUsed:   1000 * 1024,
+				NumaStat: sigar.NumaStat{
+					NumaHit:       200,
+					NumaMiss:      2,
+					NumaForeign:   3,
+					InterleaveHit: 4,
+					LocalNode:     5,
+					OtherNode:     6,
+				},
+			}))
+			Expect(nodeList.List[1].Id).To(Equal(1))
+			Expect(nodeList.List[1].Cpus).To(Equal([]int{4, 5, 7}))
+			Expect(nodeList.List[1].Distances).To(Equal([]int{20, 10}))
+			Expect(nodeList.List[1].NumaStat.NumaHit).To(Equal(uint64(100)))
+		})
+
+		It("maps CPUs to their node", func() {
+			nodeList := sigar.NumaNodeList{}
+			err := nodeList.Get()
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(nodeList.NodeOfCpu(6)).To(Equal(0))
+			Expect(nodeList.NodeOfCpu(7)).To(Equal(1))
+			Expect(nodeList.NodeOfCpu(8)).To(Equal(-1))
+		})
+
+		It("reports kernels without NUMA support as not supported", func() {
+			err := os.RemoveAll(nodeDir)
+			Expect(err).ToNot(HaveOccurred())
+
+			nodeList := sigar.NumaNodeList{}
+			err = nodeList.Get()
+			Expect(sigar.IsNotSupported(err)).To(BeTrue())
+		})
+
+		It("returns a parse error for malformed meminfo", func() {
+			err := ioutil.WriteFile(nodeDir+"/node0/meminfo", []byte("Node 0 MemTotal: lots kB\n"), 0444)
+			Expect(err).ToNot(HaveOccurred())
+
+			nodeList := sigar.NumaNodeList{}
+			err = nodeList.Get()
+			Expect(sigar.IsParse(err)).To(BeTrue())
+			Expect(err.(*sigar.Error).Field).To(Equal("MemTotal"))
+		})
 	})
 
 	Describe("Swap", func() {
@@ -302,6 +945,310 @@ DirectMap2M:      333824 kB
 		})
 	})
 
+	Describe("BlockDeviceList", func() {
+		var blockDir string
+
+		writeBlock := func(file, contents string) {
+			err := os.MkdirAll(filepath.Dir(blockDir+"/"+file), 0755)
+			Expect(err).ToNot(HaveOccurred())
+			err = ioutil.WriteFile(blockDir+"/"+file, []byte(contents+"\n"), 0444)
+			Expect(err).ToNot(HaveOccurred())
+		}
+
+		BeforeEach(func() {
+			blockDir = sysd + "/block"
+			writeBlock("nvme0n1/dev", "259:0")
+			writeBlock("nvme0n1/size", "1000215216")
+			writeBlock("nvme0n1/removable", "0")
+			writeBlock("nvme0n1/ro", "0")
+			writeBlock("nvme0n1/queue/rotational", "0")
+			writeBlock("nvme0n1/queue/logical_block_size", "512")
+			writeBlock("nvme0n1/queue/physical_block_size", "4096")
+			writeBlock("nvme0n1/queue/scheduler", "[none] mq-deadline")
+			writeBlock("nvme0n1/device/model", "Samsung SSD 970 EVO 500GB")
+			writeBlock("nvme0n1/device/serial", "S466NX0K")
+			writeBlock("nvme0n1/nvme0n1p1/dev", "259:1")
+			writeBlock("nvme0n1/nvme0n1p1/size", "1048576")
+			writeBlock("nvme0n1/nvme0n1p1/partition", "1")
+			writeBlock("nvme0n1/nvme0n1p2/dev", "259:2")
+			writeBlock("nvme0n1/nvme0n1p2/size", "999164559")
+			writeBlock("nvme0n1/nvme0n1p2/partition", "2")
+
+			writeBlock("dm-0/dev", "253:0")
+			writeBlock("dm-0/size", "209715200")
+			writeBlock("dm-0/queue/rotational", "0")
+			writeBlock("dm-0/dm/name", "vg--data-lv_root")
+			writeBlock("dm-0/dm/uuid", "LVM-abcdef")
+			writeBlock("dm-0/slaves/nvme0n1p2", "")
+
+			writeBlock("loop0/dev", "7:0")
+			writeBlock("loop0/size", "0")
+		})
+
+		It("lists disks followed by their partitions", func() {
+			blockDevices := sigar.BlockDeviceList{}
+			err := blockDevices.Get()
+			Expect(err).ToNot(HaveOccurred())
+
+			names := []string{}
+			for _, device := range blockDevices.List {
+				names = append(names, device.Name)
+			}
+			Expect(names).To(Equal([]string{"dm-0", "loop0", "nvme0n1", "nvme0n1p1", "nvme0n1p2"}))
+
+			Expect(blockDevices.List[2]).To(Equal(sigar.BlockDevice{
+				Name:               "nvme0n1",
+				Major:              259,
+				Size:               1000215216 * 512,
+				LogicalSectorSize:  512,
+				PhysicalSectorSize: 4096,
+				Model:              "Samsung SSD 970 EVO 500GB",
+				Serial:             "S466NX0K",
+				Scheduler:          "none",
+			}))
+
+			partition := blockDevices.List[3]
+			Expect(partition.IsPartition()).To(BeTrue())
+			Expect(partition.Parent).To(Equal("nvme0n1"))
+			Expect(partition.Minor).To(Equal(uint64(1)))
+			Expect(partition.Size).To(Equal(uint64(1048576 * 512)))
+			Expect(partition.Model).To(Equal("Samsung SSD 970 EVO 500GB"))
+		})
+
+		It("resolves device-mapper and LVM names", func() {
+			blockDevices := sigar.BlockDeviceList{}
+			err := blockDevices.Get()
+			Expect(err).ToNot(HaveOccurred())
+
+			dm := blockDevices.List[0]
+			Expect(dm.MapperName).To(Equal("vg--data-lv_root"))
+			Expect(dm.VolumeGroup).To(Equal("vg-data"))
+			Expect(dm.LogicalVolume).To(Equal("lv_root"))
+			Expect(dm.Slaves).To(Equal([]string{"nvme0n1p2"}))
+		})
+
+		It("decides which devices DiskList reports as whole disks", func() {
+			diskstatContents := "   7       0 loop0 0 0 0 0 0 0 0 0 0 0 0\n" +
+				" 259       0 nvme0n1 10 0 80 1 0 0 0 0 0 1 1\n" +
+				" 259       1 nvme0n1p1 5 0 40 1 0 0 0 0 0 1 1\n" +
+				" 259       2 nvme0n1p2 5 0 40 1 0 0 0 0 0 1 1\n" +
+				" 253       0 dm-0 3 0 24 1 0 0 0 0 0 1 1\n"
+			err := ioutil.WriteFile(procd+"/diskstats", []byte(diskstatContents), 0444)
+			Expect(err).ToNot(HaveOccurred())
+
+			diskList := sigar.DiskList{}
+			err = diskList.Get()
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(diskList.List).To(HaveLen(2))
+			Expect(diskList.List).To(HaveKey("nvme0n1"))
+			Expect(diskList.List).To(HaveKey("dm-0"))
+		})
+	})
+
+	Describe("MdArrayList", func() {
+		BeforeEach(func() {
+			mdstatContents := `Personalities : [raid1] [raid6] [raid5] [raid4]
+md0 : active raid1 sdb1[1] sda1[0]
+      1048512 blocks super 1.2 [2/2] [UU]
+
+md1 : active raid5 sdd1[3](F) sdc1[2] sdb2[1] sda2[0] sde1[4](S)
+      2095104 blocks super 1.2 level 5, 512k chunk, algorithm 2 [3/2] [UU_]
+      [==>..................]  recovery = 12.6% (132480/1047552) finish=0.5min speed=26496K/sec
+
+md2 : inactive sdf1[0](S)
+      1048512 blocks super 1.2
+
+unused devices: <none>
+`
+			err := ioutil.WriteFile(procd+"/mdstat", []byte(mdstatContents), 0444)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("parses arrays, members and sync progress from mdstat", func() {
+			mdArrays := sigar.MdArrayList{}
+			err := mdArrays.Get()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(mdArrays.List).To(HaveLen(3))
+
+			Expect(mdArrays.List[0]).To(Equal(sigar.MdArray{
+				Name:        "md0",
+				Active:      true,
+				State:       "active",
+				Level:       "raid1",
+				Size:        1048512 * 1024,
+				RaidDisks:   2,
+				ActiveDisks: 2,
+				Members: []sigar.MdMember{
+					{Name: "sdb1", Slot: 1, State: "in_sync"},
+					{Name: "sda1", Slot: 0, State: "in_sync"},
+				},
+				SyncAction: "idle",
+			}))
+			Expect(mdArrays.List[0].IsDegraded()).To(BeFalse())
+
+			md1 := mdArrays.List[1]
+			Expect(md1.Level).To(Equal("raid5"))
+			Expect(md1.IsDegraded()).To(BeTrue())
+			Expect(md1.Degraded).To(Equal(1))
+			Expect(md1.Failed).To(Equal(1))
+			Expect(md1.Spares).To(Equal(1))
+			Expect(md1.Members[0]).To(Equal(sigar.MdMember{Name: "sdd1", Slot: -1, State: "faulty"}))
+			Expect(md1.SyncAction).To(Equal("recover"))
+			Expect(md1.SyncCompleted).To(BeNumerically("~", 0.1265, 0.0001))
+			Expect(md1.SyncEta).To(Equal(30 * time.Second))
+			Expect(md1.SyncSpeed).To(Equal(uint64(26496 * 1024)))
+
+			md2 := mdArrays.List[2]
+			Expect(md2.Active).To(BeFalse())
+			Expect(md2.Level).To(BeEmpty())
+			Expect(md2.Members).To(Equal([]sigar.MdMember{{Name: "sdf1", Slot: -1, State: "spare"}}))
+		})
+
+		It("prefers the array and member states from sysfs", func() {
+			mdDir := sysd + "/block/md1/md"
+			err := os.MkdirAll(mdDir+"/dev-sdc1", 0755)
+			Expect(err).ToNot(HaveOccurred())
+			err = os.MkdirAll(mdDir+"/dev-sdb2", 0755)
+			Expect(err).ToNot(HaveOccurred())
+			files := map[string]string{
+				"array_state":    "clean\n",
+				"degraded":       "2\n",
+				"sync_action":    "recover\n",
+				"dev-sdc1/state": "in_sync,write_mostly\n",
+				"dev-sdc1/slot":  "2\n",
+				"dev-sdb2/state": "faulty,write_error\n",
+				"dev-sdb2/slot":  "none\n",
+			}
+			for name, contents := range files {
+				err = ioutil.WriteFile(mdDir+"/"+name, []byte(contents), 0444)
+				Expect(err).ToNot(HaveOccurred())
+			}
+
+			mdArrays := sigar.MdArrayList{}
+			err = mdArrays.Get()
+			Expect(err).ToNot(HaveOccurred())
+
+			md1 := mdArrays.List[1]
+			Expect(md1.State).To(Equal("clean"))
+			Expect(md1.Degraded).To(Equal(2))
+			Expect(md1.Members[1]).To(Equal(sigar.MdMember{Name: "sdc1", Slot: 2, State: "in_sync,write_mostly"}))
+			Expect(md1.Members[2]).To(Equal(sigar.MdMember{Name: "sdb2", Slot: -1, State: "faulty,write_error"}))
+			Expect(md1.Failed).To(Equal(2))
+			Expect(md1.Spares).To(Equal(1))
+		})
+
+		It("reports kernels without md as not supported", func() {
+			err := os.Remove(procd + "/mdstat")
+			Expect(err).ToNot(HaveOccurred())
+
+			mdArrays := sigar.MdArrayList{}
+			err = mdArrays.Get()
+			Expect(sigar.IsNotSupported(err)).To(BeTrue())
+		})
+	})
+
+	Describe("FileSystemList", func() {
+		mountinfoContents := "22 1 253:1 / / rw,relatime shared:1 - ext4 /dev/mapper/vg-root rw,errors=remount-ro\n" +
+			"23 22 0:21 / /proc rw,nosuid,nodev,noexec,relatime shared:12 - proc proc rw\n" +
+			"24 22 0:5 / /dev/shm rw,nosuid,nodev shared:3 - tmpfs tmpfs rw,size=65536k\n" +
+			"25 22 0:45 / /mnt/my\\040share rw,relatime - nfs4 nas:/export ro,vers=4.2\n" +
+			"26 22 253:1 /var/lib/data /srv ro,relatime master:1 shared:7 - ext4 /dev/mapper/vg-root rw\n" +
+			"27 22 0:50 / /var/lib/docker/overlay2/merged rw,relatime - overlay overlay rw,lowerdir=/l\n"
+
+		BeforeEach(func() {
+			err := os.MkdirAll(procd+"/self", 0755)
+			Expect(err).ToNot(HaveOccurred())
+			err = ioutil.WriteFile(procd+"/self/mountinfo", []byte(mountinfoContents), 0444)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("reads mounts from mountinfo", func() {
+			fsList := sigar.FileSystemList{}
+			err := fsList.Get()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(fsList.List).To(HaveLen(6))
+
+			Expect(fsList.List[0]).To(Equal(sigar.FileSystem{
+				DirName:      "/",
+				DevName:      "/dev/mapper/vg-root",
+				TypeName:     "local",
+				SysTypeName:  "ext4",
+				Options:      "rw,relatime",
+				Flags:        syscall.MS_RELATIME,
+				Class:        sigar.FileSystemLocal,
+				MountId:      22,
+				ParentId:     1,
+				Major:        253,
+				Minor:        1,
+				Root:         "/",
+				Propagation:  "shared:1",
+				SuperOptions: "rw,errors=remount-ro",
+			}))
+
+			bind := fsList.List[4]
+			Expect(bind.Root).To(Equal("/var/lib/data"))
+			Expect(bind.Propagation).To(Equal("master:1 shared:7"))
+			Expect(bind.Flags & syscall.MS_RDONLY).ToNot(BeZero())
+		})
+
+		It("classifies mounts", func() {
+			fsList := sigar.FileSystemList{}
+			err := fsList.Get()
+			Expect(err).ToNot(HaveOccurred())
+
+			classes := []sigar.FileSystemClass{}
+			for _, fs := range fsList.List {
+				classes = append(classes, fs.Class)
+			}
+			Expect(classes).To(Equal([]sigar.FileSystemClass{
+				sigar.FileSystemLocal,
+				sigar.FileSystemPseudo,
+				sigar.FileSystemPseudo,
+				sigar.FileSystemNetwork,
+				sigar.FileSystemLocal,
+				sigar.FileSystemOverlay,
+			}))
+			Expect(fsList.List[3].DirName).To(Equal("/mnt/my share"))
+		})
+
+		It("reads the mounts of another process", func() {
+			err := os.MkdirAll(procd+"/42", 0755)
+			Expect(err).ToNot(HaveOccurred())
+			err = ioutil.WriteFile(procd+"/42/mountinfo", []byte("100 99 0:60 / / rw - overlay overlay rw\n"), 0444)
+			Expect(err).ToNot(HaveOccurred())
+
+			fsList := sigar.FileSystemList{}
+			err = fsList.GetPid(42)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(fsList.List).To(HaveLen(1))
+			Expect(fsList.List[0].Class).To(Equal(sigar.FileSystemOverlay))
+
+			err = fsList.GetPid(43)
+			Expect(sigar.IsProcessGone(err)).To(BeTrue())
+		})
+
+		It("falls back to mtab under the etc root", func() {
+			err := os.Remove(procd + "/self/mountinfo")
+			Expect(err).ToNot(HaveOccurred())
+			err = ioutil.WriteFile(etcd+"/mtab", []byte("/dev/sda1 / ext4 rw,noatime 0 0\n"), 0444)
+			Expect(err).ToNot(HaveOccurred())
+
+			fsList := sigar.FileSystemList{}
+			err = fsList.Get()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(fsList.List).To(Equal([]sigar.FileSystem{{
+				DirName:     "/",
+				DevName:     "/dev/sda1",
+				TypeName:    "local",
+				SysTypeName: "ext4",
+				Options:     "rw,noatime",
+				Flags:       syscall.MS_NOATIME,
+				Class:       sigar.FileSystemLocal,
+			}}))
+		})
+	})
+
 	Describe("DiskIO", func() {
 		var diskstatFile string
 		var partitionsFile string
@@ -379,6 +1326,58 @@ major minor  #blocks  name
 			Expect(ioStat.List["sda"].WriteTimeMs).To(Equal(uint64(1397746)))
 			Expect(ioStat.List["sda"].IoTimeMs).To(Equal(uint64(50380)))
 		})
+
+		It("reads the discard and flush columns of newer kernels", func() {
+			diskstatContents := "   8       0 sda 7089 1514 243714 6329 86342 820181 7159936 1397746 2 50380 1404142 12 3 4096 40 500 900\n"
+			err := ioutil.WriteFile(diskstatFile, []byte(diskstatContents), 0444)
+			Expect(err).ToNot(HaveOccurred())
+
+			ioStat := sigar.DiskList{}
+			err = ioStat.Get()
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(ioStat.List["sda"]).To(Equal(sigar.DiskIo{
+				ReadOps:          7089,
+				ReadMerged:       1514,
+				ReadBytes:        243714 * 512,
+				ReadTimeMs:       6329,
+				WriteOps:         86342,
+				WriteMerged:      820181,
+				WriteBytes:       7159936 * 512,
+				WriteTimeMs:      1397746,
+				IoInProgress:     2,
+				IoTimeMs:         50380,
+				WeightedIoTimeMs: 1404142,
+				DiscardOps:       12,
+				DiscardMerged:    3,
+				DiscardBytes:     4096 * 512,
+				DiscardTimeMs:    40,
+				FlushOps:         500,
+				FlushTimeMs:      900,
+			}))
+		})
+
+		It("returns an error when partitions are unreadable", func() {
+			err := os.Remove(procd + "/partitions")
+			Expect(err).ToNot(HaveOccurred())
+
+			ioStat := sigar.DiskList{}
+			err = ioStat.Get()
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("reports devices that failed to parse", func() {
+			err := ioutil.WriteFile(procd+"/diskstats", []byte("   8       0 sda x 50 243714 6329 86342 50951 7159936 1397746 0 50380 1403992\n"), 0444)
+			Expect(err).ToNot(HaveOccurred())
+
+			ioStat := sigar.DiskList{}
+			err = ioStat.Get()
+			Expect(sigar.IsPartial(err)).To(BeTrue())
+
+			failed := err.(*sigar.PartialError).Failed["sda"]
+			Expect(sigar.IsParse(failed)).To(BeTrue())
+			Expect(failed.(*sigar.Error).Path).To(Equal(procd + "/diskstats"))
+		})
 	})
 
 	Describe("NetProtoV4", func() {
@@ -880,6 +1879,36 @@ sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid
 			Expect(connList.List[0].Inode).To(Equal(uint64(201786)))
 			Expect(connList.List[0].String()).To(Equal("raw :::58 <-> :::0"))
 		})
+
+		It("lists every protocol and resolves the pids once", func() {
+			header := "sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode ref pointer drops\n"
+			tables := map[string]string{
+				"tcp":  header + "   0: 00000000:0016 00000000:0000 0A 00000000:00000123 00:00000000 00000000     0        0 12095 1 ffff880296063500 99 0 0 10 -1\n",
+				"udp":  header,
+				"raw":  header,
+				"raw6": header + "  58: 00000000000000000000000000000000:003A 00000000000000000000000000000000:0000 07 00000000:00000000 00:00000000 00000000     0        0 201786 2 ffff88029a347000 0\n",
+			}
+			err := os.MkdirAll(procd+"/net", 0777)
+			Expect(err).ToNot(HaveOccurred())
+			for name, contents := range tables {
+				err = ioutil.WriteFile(procd+"/net/"+name, []byte(contents), 0444)
+				Expect(err).ToNot(HaveOccurred())
+			}
+			err = os.MkdirAll(procd+"/10/fd", 0777)
+			Expect(err).ToNot(HaveOccurred())
+			err = os.Symlink("socket:[201786]", procd+"/10/fd/3")
+			Expect(err).ToNot(HaveOccurred())
+
+			connList := sigar.NetConnList{}
+			err = connList.Get()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(connList.V4).To(HaveLen(1))
+			Expect(connList.V4[0].Proto).To(Equal(sigar.ConnProtoTcp))
+			Expect(connList.V4[0].Pid).To(Equal(0))
+			Expect(connList.V6).To(HaveLen(1))
+			Expect(connList.V6[0].Proto).To(Equal(sigar.ConnProtoRaw))
+			Expect(connList.V6[0].Pid).To(Equal(10))
+		})
 	})
 
 	Describe("SystemInfo", func() {
@@ -966,10 +1995,447 @@ DISTRIB_DESCRIPTION="Ubuntu 12.04.5 LTS"
 				Expect(err).To(HaveOccurred())
 				Expect(sd.Description).To(Equal(""))
 			})
+
+			It("errors when redhat-release is present but unreadable", func() {
+				err := os.MkdirAll(redhatReleaseFile, 0777)
+				Expect(err).ToNot(HaveOccurred())
+
+				err = sd.Get()
+				Expect(err).To(HaveOccurred())
+			})
+		})
+	})
+
+	Describe("GetContext", func() {
+		var fifo string
+
+		BeforeEach(func() {
+			err := os.MkdirAll(procd+"/10/", 0777)
+			Expect(err).ToNot(HaveOccurred())
+			// Opening a FIFO without a writer blocks, like a read of a hung process
+			fifo = procd + "/10/stat"
+			err = syscall.Mkfifo(fifo, 0644)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			// Release the blocked reader, and replace the FIFO so later reads don't block
+			statLine := "10 (watchdog/1) S 2 0 0 11 -1 2216722752 0 0 0 0 0 142 0 0 -100 0 1 0 4 0 0 18446744073709551615 0 0 0 0 0 0 0 2147483647 0 18446744073709551615 0 0 17 1 99 1 0 0 0"
+			f, err := os.OpenFile(fifo, os.O_RDWR, 0)
+			Expect(err).ToNot(HaveOccurred())
+			err = ioutil.WriteFile(procd+"/10/stat.tmp", []byte(statLine), 0644)
+			Expect(err).ToNot(HaveOccurred())
+			err = os.Rename(procd+"/10/stat.tmp", fifo)
+			Expect(err).ToNot(HaveOccurred())
+			f.WriteString(statLine)
+			f.Close()
+		})
+
+		It("returns a timeout error naming the stuck pid", func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+			defer cancel()
+
+			processList := sigar.ProcessList{}
+			err := processList.GetContext(ctx)
+			Expect(err).To(HaveOccurred())
+
+			timeoutErr, ok := err.(*sigar.TimeoutError)
+			Expect(ok).To(BeTrue())
+			Expect(timeoutErr.Pid).To(Equal(10))
+			Expect(timeoutErr.Path).To(Equal(procd + "/10/"))
+			Expect(timeoutErr.Timeout()).To(BeTrue())
+		})
+
+		It("returns the context error when cancelled", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+
+			processList := sigar.ProcessList{}
+			err := processList.GetContext(ctx)
+			Expect(err).To(Equal(context.Canceled))
+		})
+	})
+
+	Describe("LoadAverage", func() {
+		It("returns an error when loadavg is unreadable", func() {
+			avg := sigar.LoadAverage{}
+			err := avg.Get()
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("returns a parse error naming the field", func() {
+			err := ioutil.WriteFile(procd+"/loadavg", []byte("0.10 abc 0.30 1/100 1000"), 0644)
+			Expect(err).ToNot(HaveOccurred())
+
+			avg := sigar.LoadAverage{}
+			err = avg.Get()
+			Expect(sigar.IsParse(err)).To(BeTrue())
+			Expect(err.(*sigar.Error).Field).To(Equal("Five"))
+			Expect(err.(*sigar.Error).Path).To(Equal(procd + "/loadavg"))
+		})
+	})
+
+	Describe("DeletedOpenFiles", func() {
+		var dataDir string
+
+		writeProcess := func(pid, name string, fds map[string]string) {
+			statLine := pid + " (" + name + ") S 1 0 0 0 -1 0 0 0 0 0 0 0 0 0 20 0 1 0 4 0 0 18446744073709551615 0 0 0 0 0 0 0 0 0 0 0 0 17 0 0 0 0 0 0"
+			err := os.MkdirAll(procd+"/"+pid+"/fd", 0777)
+			Expect(err).ToNot(HaveOccurred())
+			err = ioutil.WriteFile(procd+"/"+pid+"/stat", []byte(statLine), 0644)
+			Expect(err).ToNot(HaveOccurred())
+			for fd, target := range fds {
+				err = os.Symlink(target, procd+"/"+pid+"/fd/"+fd)
+				Expect(err).ToNot(HaveOccurred())
+			}
+		}
+
+		BeforeEach(func() {
+			var err error
+			dataDir, err = ioutil.TempDir("", "sigarTests")
+			Expect(err).ToNot(HaveOccurred())
+
+			// The fd links point at real files named like deleted ones, so that
+			// stat through them works
+			err = ioutil.WriteFile(dataDir+"/app.log (deleted)", make([]byte, 1000), 0644)
+			Expect(err).ToNot(HaveOccurred())
+			err = ioutil.WriteFile(dataDir+"/current.log", make([]byte, 10), 0644)
+			Expect(err).ToNot(HaveOccurred())
+
+			stat := syscall.Stat_t{}
+			err = syscall.Stat(dataDir, &stat)
+			Expect(err).ToNot(HaveOccurred())
+			dev := fmt.Sprintf("%d:%d", (stat.Dev>>8)&0xfff, stat.Dev&0xff|(stat.Dev>>12)&0xfff00)
+			mountinfo := "22 1 " + dev + " / / rw - ext4 /dev/sda1 rw\n" +
+				"23 22 " + dev + " /data " + dataDir + " rw - ext4 /dev/sda1 rw\n"
+			err = os.MkdirAll(procd+"/self", 0755)
+			Expect(err).ToNot(HaveOccurred())
+			err = ioutil.WriteFile(procd+"/self/mountinfo", []byte(mountinfo), 0444)
+			Expect(err).ToNot(HaveOccurred())
+
+			writeProcess("10", "rsyslogd", map[string]string{
+				"3": dataDir + "/app.log (deleted)",
+				"4": dataDir + "/current.log",
+				"5": "/memfd:jit (deleted)",
+				"6": "socket:[12345]",
+			})
+			writeProcess("11", "logrotate", map[string]string{
+				"7": dataDir + "/app.log (deleted)",
+			})
+		})
+
+		AfterEach(func() {
+			os.RemoveAll(dataDir)
+		})
+
+		It("finds the deleted files each process holds open", func() {
+			files := sigar.DeletedOpenFiles{}
+			err := files.Get()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(files.List).To(HaveLen(2))
+
+			file := files.List[0]
+			Expect(file.Pid).To(Equal(10))
+			Expect(file.ProcessName).To(Equal("rsyslogd"))
+			Expect(file.Fd).To(Equal(3))
+			Expect(file.Path).To(Equal(dataDir + "/app.log"))
+			Expect(file.Size).To(Equal(uint64(1000)))
+			Expect(file.Inode).ToNot(BeZero())
+			Expect(file.DirName).To(Equal(dataDir))
+
+			Expect(files.List[1].Pid).To(Equal(11))
+			Expect(files.List[1].Fd).To(Equal(7))
+		})
+
+		It("counts a file open in several processes once", func() {
+			files := sigar.DeletedOpenFiles{}
+			err := files.Get()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(files.Reclaimable()).To(Equal(map[string]uint64{dataDir: 1000}))
+		})
+	})
+
+	Describe("ProcFdList", func() {
+		var dataDir string
+
+		BeforeEach(func() {
+			var err error
+			dataDir, err = ioutil.TempDir("", "sigarTests")
+			Expect(err).ToNot(HaveOccurred())
+			err = ioutil.WriteFile(dataDir+"/data.db", []byte("data"), 0644)
+			Expect(err).ToNot(HaveOccurred())
+
+			err = os.MkdirAll(procd+"/10/fd", 0777)
+			Expect(err).ToNot(HaveOccurred())
+			err = os.MkdirAll(procd+"/10/fdinfo", 0777)
+			Expect(err).ToNot(HaveOccurred())
+			err = os.MkdirAll(procd+"/10/net", 0777)
+			Expect(err).ToNot(HaveOccurred())
+
+			links := map[string]string{
+				"0":  "/dev/null",
+				"3":  "socket:[12095]",
+				"4":  "pipe:[4242]",
+				"5":  "anon_inode:[eventfd]",
+				"6":  "anon_inode:[eventpoll]",
+				"7":  dataDir + "/data.db",
+				"10": dataDir,
+			}
+			for fd, target := range links {
+				err = os.Symlink(target, procd+"/10/fd/"+fd)
+				Expect(err).ToNot(HaveOccurred())
+			}
+			err = ioutil.WriteFile(procd+"/10/fdinfo/7", []byte("pos:\t42\nflags:\t02100002\nmnt_id:\t25\nino:\t1234\n"), 0444)
+			Expect(err).ToNot(HaveOccurred())
+
+			tcp := `  sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode
+   0: 00000000:0016 00000000:0000 0A 00000000:00000123 00:00000000 00000000     0        0 12095 1 ffff880296063500 99 0 0 10 -1
+`
+			err = ioutil.WriteFile(procd+"/10/net/tcp", []byte(tcp), 0444)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			os.RemoveAll(dataDir)
+		})
+
+		It("resolves the type of each fd", func() {
+			fdList := sigar.ProcFdList{}
+			err := fdList.Get(10)
+			Expect(err).ToNot(HaveOccurred())
+
+			fds := []int{}
+			types := []sigar.ProcFdType{}
+			for _, fd := range fdList.List {
+				fds = append(fds, fd.Fd)
+				types = append(types, fd.Type)
+			}
+			Expect(fds).To(Equal([]int{0, 3, 4, 5, 6, 7, 10}))
+			Expect(types).To(Equal([]sigar.ProcFdType{
+				sigar.FdDevice,
+				sigar.FdSocket,
+				sigar.FdPipe,
+				sigar.FdEventFd,
+				sigar.FdAnonInode,
+				sigar.FdFile,
+				sigar.FdDirectory,
+			}))
+			Expect(fdList.List[2].Inode).To(Equal(uint64(4242)))
+		})
+
+		It("reads the position and flags from fdinfo", func() {
+			fdList := sigar.ProcFdList{}
+			err := fdList.Get(10)
+			Expect(err).ToNot(HaveOccurred())
+
+			file := fdList.List[5]
+			Expect(file.Path).To(Equal(dataDir + "/data.db"))
+			Expect(file.Pos).To(Equal(uint64(42)))
+			Expect(file.Flags).To(Equal(uint64(02100002)))
+			Expect(file.Flags & syscall.O_ACCMODE).To(Equal(uint64(syscall.O_RDWR)))
+			Expect(file.MntId).To(Equal(25))
+			Expect(file.Inode).ToNot(BeZero())
+		})
+
+		It("joins sockets with the connections of the process", func() {
+			fdList := sigar.ProcFdList{}
+			err := fdList.Get(10)
+			Expect(err).ToNot(HaveOccurred())
+
+			socket := fdList.List[1]
+			Expect(socket.Inode).To(Equal(uint64(12095)))
+			Expect(socket.Socket).ToNot(BeNil())
+			Expect(socket.Socket.Proto).To(Equal(sigar.ConnProtoTcp))
+			Expect(socket.Socket.Status).To(Equal(sigar.ConnStateListen))
+			Expect(socket.Socket.LocalPort).To(Equal(uint64(22)))
+			Expect(socket.Socket.Pid).To(Equal(10))
+		})
+
+		It("reads the sockets of a network namespace once for all its processes", func() {
+			err := os.MkdirAll(procd+"/10/ns", 0777)
+			Expect(err).ToNot(HaveOccurred())
+			err = ioutil.WriteFile(procd+"/10/ns/net", []byte{}, 0444)
+			Expect(err).ToNot(HaveOccurred())
+			err = os.MkdirAll(procd+"/11/ns", 0777)
+			Expect(err).ToNot(HaveOccurred())
+			err = os.Link(procd+"/10/ns/net", procd+"/11/ns/net")
+			Expect(err).ToNot(HaveOccurred())
+			err = os.MkdirAll(procd+"/11/fd", 0777)
+			Expect(err).ToNot(HaveOccurred())
+			err = os.Symlink("socket:[12095]", procd+"/11/fd/4")
+			Expect(err).ToNot(HaveOccurred())
+
+			fdList := sigar.ProcFdList{}
+			err = fdList.Get(10)
+			Expect(err).ToNot(HaveOccurred())
+
+			// 11 has no tables of its own, so its socket must come from those of 10
+			err = fdList.Get(11)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(fdList.List).To(HaveLen(1))
+			Expect(fdList.List[0].Socket).ToNot(BeNil())
+			Expect(fdList.List[0].Socket.LocalPort).To(Equal(uint64(22)))
+			Expect(fdList.List[0].Socket.Pid).To(Equal(11))
+		})
+
+		It("reports a missing process as gone", func() {
+			fdList := sigar.ProcFdList{}
+			err := fdList.Get(11)
+			Expect(sigar.IsProcessGone(err)).To(BeTrue())
+		})
+	})
+
+	Describe("ProcLimits", func() {
+		limitsContents := `Limit                     Soft Limit           Hard Limit           Units     
+Max cpu time              unlimited            unlimited            seconds   
+Max file size             unlimited            unlimited            bytes     
+Max data size             unlimited            unlimited            bytes     
+Max stack size            8388608              unlimited            bytes     
+Max core file size        0                    unlimited            bytes     
+Max resident set          unlimited            unlimited            bytes     
+Max processes             63438                63438                processes 
+Max open files            1024                 524288               files     
+Max locked memory         8388608              8388608              bytes     
+Max address space         unlimited            unlimited            bytes     
+Max file locks            unlimited            unlimited            locks     
+Max pending signals       63438                63438                signals   
+Max msgqueue size         819200               819200               bytes     
+Max nice priority         0                    0                    
+Max realtime priority     0                    0                    
+Max realtime timeout      unlimited            unlimited            us        
+`
+
+		BeforeEach(func() {
+			err := os.MkdirAll(procd+"/10/fd", 0777)
+			Expect(err).ToNot(HaveOccurred())
+			err = ioutil.WriteFile(procd+"/10/limits", []byte(limitsContents), 0444)
+			Expect(err).ToNot(HaveOccurred())
+			for _, fd := range []string{"0", "1", "2"} {
+				err = os.Symlink("/dev/null", procd+"/10/fd/"+fd)
+				Expect(err).ToNot(HaveOccurred())
+			}
+		})
+
+		It("parses every soft and hard limit", func() {
+			limits := sigar.ProcLimits{}
+			err := limits.Get(10)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(limits.OpenFiles).To(Equal(sigar.ProcLimit{Soft: 1024, Hard: 524288}))
+			Expect(limits.StackSize).To(Equal(sigar.ProcLimit{Soft: 8388608, Hard: sigar.RlimInfinity}))
+			Expect(limits.CoreFileSize.Soft).To(Equal(uint64(0)))
+			Expect(limits.Processes.Hard).To(Equal(uint64(63438)))
+			Expect(limits.AddressSpace.Soft).To(Equal(sigar.RlimInfinity))
+			Expect(limits.NicePriority).To(Equal(sigar.ProcLimit{}))
+			Expect(limits.RealtimeTimeout.Hard).To(Equal(sigar.RlimInfinity))
+		})
+
+		It("counts open fds against the open files limit", func() {
+			fd := sigar.ProcFd{}
+			err := fd.Get(10)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(fd).To(Equal(sigar.ProcFd{Open: 3, SoftLimit: 1024, HardLimit: 524288}))
+			Expect(fd.UsePercent()).To(BeNumerically("~", 0.29, 0.01))
+		})
+
+		It("lists the fd usage of every readable process", func() {
+			err := os.MkdirAll(procd+"/11", 0777)
+			Expect(err).ToNot(HaveOccurred())
+
+			usage := sigar.ProcFdUsageList{}
+			err = usage.Get()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(usage.List).To(Equal([]sigar.ProcFdUsage{
+				{Pid: 10, ProcFd: sigar.ProcFd{Open: 3, SoftLimit: 1024, HardLimit: 524288}},
+			}))
+		})
+
+		It("reports a missing process as gone", func() {
+			limits := sigar.ProcLimits{}
+			err := limits.Get(11)
+			Expect(sigar.IsProcessGone(err)).To(BeTrue())
+		})
+
+		It("reads the system wide file handles", func() {
+			err := os.MkdirAll(procd+"/sys/fs", 0777)
+			Expect(err).ToNot(HaveOccurred())
+			err = ioutil.WriteFile(procd+"/sys/fs/file-nr", []byte("9056\t0\t1000000\n"), 0444)
+			Expect(err).ToNot(HaveOccurred())
+
+			systemFd := sigar.SystemFd{}
+			err = systemFd.Get()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(systemFd).To(Equal(sigar.SystemFd{Allocated: 9056, Max: 1000000}))
+
+			err = ioutil.WriteFile(procd+"/sys/fs/file-max", []byte("2000000\n"), 0444)
+			Expect(err).ToNot(HaveOccurred())
+			err = systemFd.Get()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(systemFd.Max).To(Equal(uint64(2000000)))
 		})
 	})
 
 	Describe("Process", func() {
+		It("reports a missing process as gone", func() {
+			procState := &sigar.ProcState{}
+			err := procState.Get(10)
+			Expect(sigar.IsProcessGone(err)).To(BeTrue())
+			Expect(err.(*sigar.Error).Path).To(Equal(procd + "/10/stat"))
+			Expect(sigar.Cause(err)).To(Equal(syscall.ESRCH))
+		})
+
+		It("reports processes that failed in the process list", func() {
+			statLine := "10 (watchdog/1) S 2 0 0 11 -1 2216722752 0 0 0 0 0 142 0 0 -100 0 1 0 4 0 0 18446744073709551615 0 0 0 0 0 0 0 2147483647 0 18446744073709551615 0 0 17 1 99 1 0 0 0"
+			for _, pid := range []string{"10", "11"} {
+				err := os.MkdirAll(procd+"/"+pid, 0777)
+				Expect(err).ToNot(HaveOccurred())
+				for _, name := range []string{"io", "statm", "cmdline"} {
+					err = ioutil.WriteFile(procd+"/"+pid+"/"+name, []byte("0 0 0"), 0644)
+					Expect(err).ToNot(HaveOccurred())
+				}
+				for _, name := range []string{"exe", "cwd", "root"} {
+					err = os.Symlink("/", procd+"/"+pid+"/"+name)
+					Expect(err).ToNot(HaveOccurred())
+				}
+			}
+			err := ioutil.WriteFile(procd+"/10/stat", []byte(statLine), 0644)
+			Expect(err).ToNot(HaveOccurred())
+			err = ioutil.WriteFile(procd+"/11/stat", []byte("11 (truncated) S"), 0644)
+			Expect(err).ToNot(HaveOccurred())
+
+			processList := sigar.ProcessList{}
+			err = processList.Get()
+			Expect(sigar.IsPartial(err)).To(BeTrue())
+			Expect(err.(*sigar.PartialError).Failed).To(HaveLen(1))
+			Expect(sigar.IsParse(err.(*sigar.PartialError).Failed["11"])).To(BeTrue())
+			Expect(processList.List).To(HaveLen(2))
+		})
+
+		It("skips processes that exited during collection", func() {
+			err := os.MkdirAll(procd+"/10", 0777)
+			Expect(err).ToNot(HaveOccurred())
+
+			processList := sigar.ProcessList{}
+			err = processList.Get()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(processList.List).To(BeEmpty())
+		})
+
+		It("parses command names containing spaces", func() {
+			statLine := "10 (Web Content) S 2 0 0 11 -1 2216722752 0 0 0 0 0 142 0 0 -100 0 1 0 4 0 0 18446744073709551615 0 0 0 0 0 0 0 2147483647 0 18446744073709551615 0 0 17 1 99 1 0 0 0"
+			err := os.MkdirAll(procd+"/10/", 0777)
+			Expect(err).ToNot(HaveOccurred())
+			err = ioutil.WriteFile(procd+"/10/stat", []byte(statLine), 0444)
+			Expect(err).ToNot(HaveOccurred())
+
+			procState := &sigar.ProcState{}
+			err = procState.Get(10)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(procState.Name).To(Equal("Web Content"))
+			Expect(procState.Ppid).To(Equal(2))
+			Expect(procState.Processor).To(Equal(1))
+		})
+
 		It("GetsProcessList", func() {
 			err := os.MkdirAll(procd+"/stat", 0777)
 			Expect(err).ToNot(HaveOccurred())
@@ -1021,6 +2487,80 @@ DISTRIB_DESCRIPTION="Ubuntu 12.04.5 LTS"
 			Expect(procState.Processor).To(Equal(int(1)))
 		})
 
+		It("GetsProcessStatus", func() {
+			status := "Name:\tnginx\nUmask:\t0022\nState:\tS (sleeping)\n" +
+				"Uid:\t0\t33\t33\t33\nGid:\t0\t33\t33\t33\n" +
+				"VmPeak:\t  150000 kB\nVmSize:\t  149000 kB\nVmHWM:\t    9000 kB\n" +
+				"RssAnon:\t    2000 kB\nRssFile:\t    6000 kB\nRssShmem:\t     100 kB\nVmSwap:\t      12 kB\n" +
+				"Threads:\t4\nSigQ:\t0/63438\nSigPnd:\t0000000000000000\nShdPnd:\t0000000000000400\n" +
+				"SigBlk:\t0000000000000000\nSigIgn:\t0000000000001000\nSigCgt:\t0000000180014a07\n" +
+				"CapInh:\t0000000000000000\nCapPrm:\t0000000000000400\nCapEff:\t0000000000000400\n" +
+				"CapBnd:\t000001ffffffffff\nCapAmb:\t0000000000000000\n" +
+				"NoNewPrivs:\t1\nSeccomp:\t2\nSeccomp_filters:\t1\n" +
+				"Cpus_allowed:\tf\nCpus_allowed_list:\t0-3\n" +
+				"voluntary_ctxt_switches:\t120\nnonvoluntary_ctxt_switches:\t7\n"
+			err := os.MkdirAll(procd+"/10/", 0777)
+			Expect(err).ToNot(HaveOccurred())
+			err = ioutil.WriteFile(procd+"/10/status", []byte(status), 0444)
+			Expect(err).ToNot(HaveOccurred())
+			err = ioutil.WriteFile(etcd+"/passwd", []byte("root:x:0:0:root:/root:/bin/bash\nwww-data:x:33:33:www-data:/var/www:/usr/sbin/nologin\n"), 0444)
+			Expect(err).ToNot(HaveOccurred())
+			err = ioutil.WriteFile(etcd+"/group", []byte("root:x:0:\nwww-data:x:33:\n"), 0444)
+			Expect(err).ToNot(HaveOccurred())
+
+			procStatus := &sigar.ProcStatus{}
+			err = procStatus.Get(10)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(procStatus.Uids).To(Equal(sigar.ProcIds{Real: 0, Effective: 33, Saved: 33, FileSystem: 33}))
+			Expect(procStatus.User).To(Equal("www-data"))
+			Expect(procStatus.Group).To(Equal("www-data"))
+			Expect(procStatus.Threads).To(Equal(uint64(4)))
+			Expect(procStatus.VmPeak).To(Equal(uint64(150000 * 1024)))
+			Expect(procStatus.VmHWM).To(Equal(uint64(9000 * 1024)))
+			Expect(procStatus.VmSwap).To(Equal(uint64(12 * 1024)))
+			Expect(procStatus.RssShmem).To(Equal(uint64(100 * 1024)))
+			Expect(procStatus.VoluntaryCtxtSwitches).To(Equal(uint64(120)))
+			Expect(procStatus.NonvoluntaryCtxtSwitches).To(Equal(uint64(7)))
+			Expect(procStatus.SigSharedPending).To(Equal(uint64(1 << (int(syscall.SIGSEGV) - 1))))
+			Expect(procStatus.SigIgnored).To(Equal(uint64(1 << (int(syscall.SIGPIPE) - 1))))
+			Expect(procStatus.CapEffective).To(Equal(uint64(1 << 10))) // CAP_NET_BIND_SERVICE
+			Expect(procStatus.CapBounding).To(Equal(uint64(0x1ffffffffff)))
+			Expect(procStatus.Seccomp).To(Equal(2))
+			Expect(procStatus.NoNewPrivs).To(BeTrue())
+			Expect(procStatus.CpusAllowed).To(Equal([]int{0, 1, 2, 3}))
+
+			// A kernel thread has no memory lines
+			err = os.MkdirAll(procd+"/2/", 0777)
+			Expect(err).ToNot(HaveOccurred())
+			err = ioutil.WriteFile(procd+"/2/status", []byte("Name:\tkthreadd\nUid:\t0\t0\t0\t0\nGid:\t0\t0\t0\t0\nThreads:\t1\n"), 0444)
+			Expect(err).ToNot(HaveOccurred())
+
+			err = procStatus.Get(2)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(procStatus.User).To(Equal("root"))
+			Expect(procStatus.VmPeak).To(BeZero())
+			Expect(procStatus.RssShmem).To(BeZero())
+			Expect(procStatus.CpusAllowed).To(BeEmpty())
+		})
+
+		It("leaves names empty for ids missing from passwd and group", func() {
+			err := os.MkdirAll(procd+"/10/", 0777)
+			Expect(err).ToNot(HaveOccurred())
+			err = ioutil.WriteFile(procd+"/10/status", []byte("Uid:\t1000\t1000\t1000\t1000\nGid:\t1000\t1000\t1000\t1000\n"), 0444)
+			Expect(err).ToNot(HaveOccurred())
+
+			procStatus := &sigar.ProcStatus{}
+			err = procStatus.Get(10)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(procStatus.Uids.Real).To(Equal(uint32(1000)))
+			Expect(procStatus.User).To(BeEmpty())
+			Expect(procStatus.Group).To(BeEmpty())
+
+			err = procStatus.Get(11)
+			Expect(sigar.IsProcessGone(err)).To(BeTrue())
+		})
+
 		It("GetsProcessTime", func() {
 			// Write cpu stats file /proc/stat
 			cpuFile := procd + "/stat"
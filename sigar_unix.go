@@ -13,14 +13,22 @@ func (self *FileSystemUsage) Get(path string) error {
 		return err
 	}
 
-	bsize := stat.Bsize / 512
+	fragmentSize := statfsFragmentSize(&stat)
 
-	self.Total = (uint64(stat.Blocks) * uint64(bsize)) >> 1
-	self.Free = (uint64(stat.Bfree) * uint64(bsize)) >> 1
-	self.Avail = (uint64(stat.Bavail) * uint64(bsize)) >> 1
+	self.Total = uint64(stat.Blocks) * fragmentSize
+	self.Free = uint64(stat.Bfree) * fragmentSize
+	self.Avail = uint64(stat.Bavail) * fragmentSize
 	self.Used = self.Total - self.Free
+	self.Reserved = 0
+	if self.Free > self.Avail {
+		self.Reserved = self.Free - self.Avail
+	}
 	self.Files = stat.Files
 	self.FreeFiles = stat.Ffree
 
+	self.BlockSize = uint64(stat.Bsize)
+	self.FragmentSize = fragmentSize
+	setStatfsFlags(self, &stat)
+
 	return nil
 }
@@ -20,3 +20,12 @@ func bytePtrToString(ptr *int8) string {
 func chop(buf []byte) []byte {
 	return buf[0 : len(buf)-1]
 }
+
+// Difference between two samples of a counter. A counter that went backwards
+// was reset (e.g. an interface was recreated), so count from zero.
+func counterDelta(current, previous uint64) uint64 {
+	if current < previous {
+		return current
+	}
+	return current - previous
+}
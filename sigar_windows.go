@@ -264,6 +264,10 @@ func (self *FileSystemList) Get() error {
 	return iter.Error()
 }
 
+func (self *FileSystemList) GetPid(pid int) error {
+	return notImplemented()
+}
+
 func (self *DiskList) Get() error {
 	/* Even though these queries are % disk time and ops / sec,
 	   we read the raw PDH counter values, not the "cooked" ones.
@@ -502,6 +506,10 @@ func (self *ProcState) Get(pid int) error {
 	return nil
 }
 
+func (self *ProcStatus) Get(pid int) error {
+	return notImplemented()
+}
+
 func (self *ProcIo) Get(pid int) error {
 	proc, err := getWmiWin32ProcessResult(pid)
 	if err != nil {
@@ -844,6 +852,29 @@ func (self *NetRawV6ConnList) Get() error {
 	return notImplemented()
 }
 
+func (self *NetConnList) Get() error {
+	tcp := NetTcpConnList{}
+	if err := tcp.Get(); err != nil {
+		return err
+	}
+	udp := NetUdpConnList{}
+	if err := udp.Get(); err != nil {
+		return err
+	}
+	tcp6 := NetTcpV6ConnList{}
+	if err := tcp6.Get(); err != nil {
+		return err
+	}
+	udp6 := NetUdpV6ConnList{}
+	if err := udp6.Get(); err != nil {
+		return err
+	}
+
+	self.V4 = append(tcp.List, udp.List...)
+	self.V6 = append(tcp6.List, udp6.List...)
+	return nil
+}
+
 func (self *NetProtoV4Stats) Get() error {
 	// List of PDH counters to gather. PDH counters are retreived "raw", meaning that per-second
 	// counters are returned as monotonically increasing values despite their name
@@ -1018,6 +1049,70 @@ func (self *SystemDistribution) Get() error {
 	return nil
 }
 
+func (self *KernelStat) Get() error {
+	return notImplemented()
+}
+
+func (self *Pressure) Get() error {
+	return notImplemented()
+}
+
+func (self *Pressure) GetCgroup(path string) error {
+	return notImplemented()
+}
+
+func (self *MemInfo) Get() error {
+	return notImplemented()
+}
+
+func (self *VmStat) Get() error {
+	return notImplemented()
+}
+
+func (self *SwapList) Get() error {
+	return notImplemented()
+}
+
+func (self *NumaNodeList) Get() error {
+	return notImplemented()
+}
+
+func (self *CpuInfo) Get() error {
+	return notImplemented()
+}
+
+func (self *BlockDeviceList) Get() error {
+	return notImplemented()
+}
+
+func (self *MdArrayList) Get() error {
+	return notImplemented()
+}
+
+func (self *DeletedOpenFiles) Get() error {
+	return notImplemented()
+}
+
+func (self *SystemFd) Get() error {
+	return notImplemented()
+}
+
+func (self *ProcFdUsageList) Get() error {
+	return notImplemented()
+}
+
 func notImplemented() error {
 	return ErrNotImplemented
 }
+
+func (self *ProcFd) Get(pid int) error {
+	return notImplemented()
+}
+
+func (self *ProcLimits) Get(pid int) error {
+	return notImplemented()
+}
+
+func (self *ProcFdList) Get(pid int) error {
+	return notImplemented()
+}